@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/majd/ipatool/v2/pkg/appstore"
+)
+
+// resolveAccount returns the stored account, logging in again if the previous attempt failed
+// because the password token expired.
+// nolint:wrapcheck
+func resolveAccount(lastErr error) (appstore.Account, error) {
+	infoResult, err := dependencies.AppStore.AccountInfo()
+	if err != nil {
+		return appstore.Account{}, err
+	}
+
+	acc := infoResult.Account
+
+	if errors.Is(lastErr, appstore.ErrPasswordTokenExpired) {
+		bagOutput, err := dependencies.AppStore.Bag(appstore.BagInput{})
+		if err != nil {
+			return appstore.Account{}, fmt.Errorf("failed to get bag: %w", err)
+		}
+
+		loginResult, err := dependencies.AppStore.Login(appstore.LoginInput{
+			Email:    acc.Email,
+			Password: acc.Password,
+			Endpoint: bagOutput.AuthEndpoint,
+		})
+		if err != nil {
+			return appstore.Account{}, err
+		}
+
+		acc = loginResult.Account
+	}
+
+	return acc, nil
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bufio"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/avast/retry-go"
 	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/majd/ipatool/v2/pkg/session"
 	"github.com/majd/ipatool/v2/pkg/util"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
@@ -23,7 +25,10 @@ func authCmd() *cobra.Command {
 
 	cmd.AddCommand(loginCmd())
 	cmd.AddCommand(infoCmd())
+	cmd.AddCommand(statusCmd())
 	cmd.AddCommand(revokeCmd())
+	cmd.AddCommand(exportCmd())
+	cmd.AddCommand(importCmd())
 
 	return cmd
 }
@@ -104,11 +109,10 @@ func loginCmd() *cobra.Command {
 					return err
 				}
 
-				dependencies.Logger.Log().
-					Str("name", output.Account.Name).
-					Str("email", output.Account.Email).
-					Bool("success", true).
-					Send()
+				logResult(accountResult{
+					Name:  output.Account.Name,
+					Email: output.Account.Email,
+				})
 
 				return nil
 			},
@@ -145,17 +149,58 @@ func infoCmd() *cobra.Command {
 				return err
 			}
 
-			dependencies.Logger.Log().
-				Str("name", output.Account.Name).
-				Str("email", output.Account.Email).
-				Bool("success", true).
-				Send()
+			logResult(accountResult{
+				Name:  output.Account.Name,
+				Email: output.Account.Email,
+			})
 
 			return nil
 		},
 	}
 }
 
+// nolint:wrapcheck
+func statusCmd() *cobra.Command {
+	var probeAppID int64
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Validate the stored credentials against the App Store",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			infoResult, err := dependencies.AppStore.AccountInfo()
+			if err != nil {
+				return err
+			}
+
+			acc := infoResult.Account
+
+			output, err := dependencies.AppStore.AccountStatus(appstore.AccountStatusInput{
+				Account: acc,
+				AppID:   probeAppID,
+			})
+			if err != nil {
+				return err
+			}
+
+			logResult(authStatusResult{
+				Status:              string(output.Status),
+				Name:                acc.Name,
+				Email:               acc.Email,
+				StoreFront:          acc.StoreFront,
+				CountryCode:         output.CountryCode,
+				Pod:                 acc.Pod,
+				DirectoryServicesID: acc.DirectoryServicesID,
+			})
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64Var(&probeAppID, "probe-app-id", 0, "ID of the free app used to validate the credentials (defaults to TestFlight)")
+
+	return cmd
+}
+
 // nolint:wrapcheck
 func revokeCmd() *cobra.Command {
 	return &cobra.Command{
@@ -167,9 +212,180 @@ func revokeCmd() *cobra.Command {
 				return err
 			}
 
-			dependencies.Logger.Log().Bool("success", true).Send()
+			logResult(authRevokeResult{})
+
+			return nil
+		},
+	}
+}
+
+// nolint:wrapcheck
+func exportCmd() *cobra.Command {
+	var outputPath, passphrase string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the current session to a passphrase-encrypted archive",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			interactive, _ := cmd.Context().Value(interactiveKey).(bool)
+
+			archivePassphrase, err := resolveSessionPassphrase(passphrase, interactive)
+			if err != nil {
+				return err
+			}
+
+			accountData, err := dependencies.Keychain.Get("account")
+			if err != nil {
+				return fmt.Errorf("failed to read account from keychain: %w", err)
+			}
+
+			var acc appstore.Account
+
+			err = json.Unmarshal(accountData, &acc)
+			if err != nil {
+				return fmt.Errorf("failed to unmarshal account: %w", err)
+			}
+
+			guid := acc.GUID
+			if guid == "" {
+				macAddr, err := dependencies.Machine.MacAddress()
+				if err != nil {
+					return fmt.Errorf("failed to get mac address: %w", err)
+				}
+
+				guid = strings.ReplaceAll(strings.ToUpper(macAddr), ":", "")
+			}
+
+			cookies, err := os.ReadFile(cookieJarPath(dependencies.Machine))
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to read cookie jar: %w", err)
+			}
+
+			data, err := session.Seal(session.Bundle{
+				Account:    accountData,
+				Cookies:    cookies,
+				GUID:       guid,
+				ExportedAt: time.Now().UTC(),
+			}, archivePassphrase)
+			if err != nil {
+				return fmt.Errorf("failed to seal session: %w", err)
+			}
+
+			err = os.WriteFile(outputPath, data, 0600)
+			if err != nil {
+				return fmt.Errorf("failed to write session archive: %w", err)
+			}
+
+			logResult(authExportResult{
+				Output: outputPath,
+				Email:  acc.Email,
+			})
 
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&outputPath, "out", "", "The destination path of the session archive (required)")
+	cmd.Flags().StringVar(&passphrase, "passphrase", "", "passphrase for encrypting the session archive")
+
+	_ = cmd.MarkFlagRequired("out")
+
+	return cmd
+}
+
+// nolint:wrapcheck
+func importCmd() *cobra.Command {
+	var passphrase string
+
+	cmd := &cobra.Command{
+		Use:   "import <path>",
+		Short: "Import a session from a passphrase-encrypted archive",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			interactive, _ := cmd.Context().Value(interactiveKey).(bool)
+
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read session archive: %w", err)
+			}
+
+			archivePassphrase, err := resolveSessionPassphrase(passphrase, interactive)
+			if err != nil {
+				return err
+			}
+
+			bundle, err := session.Open(data, archivePassphrase)
+			if err != nil {
+				return fmt.Errorf("failed to open session archive: %w", err)
+			}
+
+			var acc appstore.Account
+
+			err = json.Unmarshal(bundle.Account, &acc)
+			if err != nil {
+				return fmt.Errorf("failed to unmarshal account: %w", err)
+			}
+
+			acc.GUID = bundle.GUID
+
+			accountData, err := json.Marshal(acc)
+			if err != nil {
+				return fmt.Errorf("failed to marshal account: %w", err)
+			}
+
+			err = dependencies.Keychain.Set("account", accountData)
+			if err != nil {
+				return fmt.Errorf("failed to save account in keychain: %w", err)
+			}
+
+			if len(bundle.Cookies) > 0 {
+				err = os.WriteFile(cookieJarPath(dependencies.Machine), bundle.Cookies, 0600)
+				if err != nil {
+					return fmt.Errorf("failed to write cookie jar: %w", err)
+				}
+			}
+
+			expired, err := bundle.TokenExpired(time.Now())
+			if err != nil {
+				return fmt.Errorf("failed to check token expiry: %w", err)
+			}
+
+			if expired {
+				dependencies.Logger.Log().Msg("the imported session token has expired; run `ipatool auth login` to authenticate again")
+			}
+
+			logResult(authImportResult{
+				Name:         acc.Name,
+				Email:        acc.Email,
+				ExportedAt:   bundle.ExportedAt,
+				TokenExpired: expired,
+			})
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&passphrase, "passphrase", "", "passphrase for decrypting the session archive")
+
+	return cmd
+}
+
+// resolveSessionPassphrase returns the passphrase protecting a session archive, prompting for it when needed.
+func resolveSessionPassphrase(passphrase string, interactive bool) (string, error) {
+	if passphrase != "" {
+		return passphrase, nil
+	}
+
+	if !interactive {
+		return "", errors.New("passphrase is required when not running in interactive mode; use the \"--passphrase\" flag")
+	}
+
+	dependencies.Logger.Log().Msg("enter session archive passphrase:")
+
+	bytes, err := term.ReadPassword(int(os.Stdin.Fd()))
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+
+	return string(bytes), nil
 }
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/spf13/cobra"
+)
+
+// nolint:wrapcheck
+func availabilityCmd() *cobra.Command {
+	var (
+		bundleID      string
+		platformValue string
+		countries     []string
+		concurrency   int
+		interval      time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "availability",
+		Short: "Check in which countries an app is available on the App Store",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			platform, err := appstore.ParsePlatform(platformValue)
+			if err != nil {
+				return err
+			}
+
+			output, err := dependencies.AppStore.Availability(appstore.AvailabilityInput{
+				BundleID:    bundleID,
+				Platform:    platform,
+				Countries:   countries,
+				Concurrency: concurrency,
+				Interval:    interval,
+			})
+			if err != nil {
+				return err
+			}
+
+			available := 0
+
+			for _, entry := range output.Entries {
+				if entry.Available {
+					available++
+				}
+
+				if entry.Error != nil {
+					dependencies.Logger.Verbose().
+						Err(entry.Error).
+						Str("countryCode", entry.CountryCode).
+						Msg("lookup failed")
+				}
+			}
+
+			logResult(availabilityResult{
+				BundleID:       bundleID,
+				AvailableCount: available,
+				Countries:      output.Entries,
+			})
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&bundleID, "bundle-identifier", "b", "", "The bundle identifier of the target iOS app (required)")
+	cmd.Flags().StringVar(&platformValue, "platform", "", "Platform to check: iphone, ipad, or appletv")
+	cmd.Flags().StringSliceVar(&countries, "country", nil, "Two-letter codes of the countries to check (defaults to every supported country)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "maximum number of concurrent lookups")
+	cmd.Flags().DurationVar(&interval, "request-interval", 100*time.Millisecond, "minimum delay between two consecutive lookups, used to rate limit requests")
+
+	_ = cmd.MarkFlagRequired("bundle-identifier")
+
+	return cmd
+}
@@ -11,19 +11,32 @@ import (
 	"github.com/byteness/keyring"
 	cookiejar "github.com/juju/persistent-cookiejar"
 	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/majd/ipatool/v2/pkg/config"
+	"github.com/majd/ipatool/v2/pkg/hook"
 	"github.com/majd/ipatool/v2/pkg/http"
 	"github.com/majd/ipatool/v2/pkg/keychain"
 	"github.com/majd/ipatool/v2/pkg/log"
 	"github.com/majd/ipatool/v2/pkg/util"
 	"github.com/majd/ipatool/v2/pkg/util/machine"
 	"github.com/majd/ipatool/v2/pkg/util/operatingsystem"
+	"github.com/majd/ipatool/v2/pkg/util/ratelimit"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
 
 var dependencies = Dependencies{}
-var keychainPassphrase string
+var (
+	keychainPassphrase    string
+	keychainBackend       string
+	keychainAgeIdentity   string
+	keychainAgeRecipients []string
+	keychainEnviron       []string
+	limitRate             string
+	pauseDuring           string
+	rateLimiter           ratelimit.Limiter
+	postDownloadHooks     []string
+)
 
 type Dependencies struct {
 	Logger    log.Logger
@@ -32,10 +45,12 @@ type Dependencies struct {
 	CookieJar http.CookieJar
 	Keychain  keychain.Keychain
 	AppStore  appstore.AppStore
+	Config    config.Config
+	Hooks     hook.Hooks
 }
 
 // newLogger returns a new logger instance.
-func newLogger(format OutputFormat, verbose bool) log.Logger {
+func newLogger(format OutputFormat, template string, verbose bool) log.Logger {
 	var writer io.Writer
 
 	switch format {
@@ -43,6 +58,21 @@ func newLogger(format OutputFormat, verbose bool) log.Logger {
 		writer = zerolog.SyncWriter(os.Stdout)
 	case OutputFormatText:
 		writer = log.NewWriter()
+	case OutputFormatTable:
+		writer = newFormatWriter(log.NewTableFormatter(), os.Stderr)
+	case OutputFormatCSV:
+		writer = newFormatWriter(log.NewCSVFormatter(), os.Stderr)
+	case OutputFormatYAML:
+		writer = newFormatWriter(log.NewYAMLFormatter(), os.Stdout)
+	case OutputFormatNDJSON:
+		writer = newFormatWriter(log.NewNDJSONFormatter(), os.Stdout)
+	case OutputFormatGoTemplate:
+		writer = zerolog.SyncWriter(log.NewFormatWriter(log.FormatWriterArgs{
+			Formatter:    util.Must(log.NewTemplateFormatter(template)),
+			Out:          os.Stdout,
+			Err:          os.Stderr,
+			ErrFormatter: log.NewTableFormatter(),
+		}))
 	}
 
 	return log.NewLogger(log.Args{
@@ -52,23 +82,86 @@ func newLogger(format OutputFormat, verbose bool) log.Logger {
 	)
 }
 
+// newFormatWriter returns a writer rendering the results of commands to stdout with the specified formatter.
+// Like with the JSON format, structured formats keep errors on stdout while tabular formats write them to stderr.
+func newFormatWriter(formatter log.Formatter, errWriter io.Writer) io.Writer {
+	return zerolog.SyncWriter(log.NewFormatWriter(log.FormatWriterArgs{
+		Formatter: formatter,
+		Out:       os.Stdout,
+		Err:       errWriter,
+	}))
+}
+
 // newCookieJar returns a new cookie jar instance.
 func newCookieJar(machine machine.Machine) http.CookieJar {
 	return util.Must(cookiejar.New(&cookiejar.Options{
-		Filename: filepath.Join(machine.HomeDirectory(), ConfigDirectoryName, CookieJarFileName),
+		Filename: cookieJarPath(machine),
 	}))
 }
 
-// newKeychain returns a new keychain instance.
-func newKeychain(machine machine.Machine, logger log.Logger, interactive bool) keychain.Keychain {
-	ring := util.Must(keyring.Open(keyring.Config{
-		AllowedBackends: []keyring.BackendType{
-			keyring.KeychainBackend,
-			keyring.SecretServiceBackend,
-			keyring.FileBackend,
-		},
-		ServiceName: KeychainServiceName,
-		FileDir:     filepath.Join(machine.HomeDirectory(), ConfigDirectoryName),
+// cookieJarPath returns the path of the file backing the persistent cookie jar.
+func cookieJarPath(machine machine.Machine) string {
+	return filepath.Join(machine.HomeDirectory(), ConfigDirectoryName, CookieJarFileName)
+}
+
+// newKeychain returns a new keychain instance using the specified backend.
+func newKeychain(machine machine.Machine, logger log.Logger, interactive bool, backend string) (keychain.Keychain, error) {
+	var (
+		ring keychain.Keyring
+		err  error
+	)
+
+	switch backend {
+	case KeychainBackendAge:
+		ring, err = keychain.NewAgeKeyring(keychain.AgeKeyringArgs{
+			Path:         filepath.Join(machine.HomeDirectory(), ConfigDirectoryName, AgeKeychainFileName),
+			IdentityFile: keychainAgeIdentity,
+			Recipients:   keychainAgeRecipients,
+		})
+	case KeychainBackendMemory:
+		ring = keychain.NewMemoryKeyring(keychain.ItemsFromEnvironment(keychainEnviron))
+	default:
+		ring, err = newSystemKeyring(machine, logger, interactive, backend)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s keychain: %w", keychainBackendName(backend), err)
+	}
+
+	return keychain.New(keychain.Args{Keyring: ring}), nil
+}
+
+// keychainBackendName returns the name of the backend in error messages.
+func keychainBackendName(backend string) string {
+	if backend == "" {
+		return "default"
+	}
+
+	return backend
+}
+
+// newSystemKeyring returns a keyring backed by the operating system's credential store or an encrypted file.
+func newSystemKeyring(machine machine.Machine, logger log.Logger, interactive bool, backend string) (keychain.Keyring, error) {
+	backends := []keyring.BackendType{
+		keyring.KeychainBackend,
+		keyring.SecretServiceBackend,
+		keyring.FileBackend,
+	}
+
+	switch backend {
+	case KeychainBackendKeychain:
+		backends = []keyring.BackendType{keyring.KeychainBackend}
+	case KeychainBackendSecretService:
+		backends = []keyring.BackendType{keyring.SecretServiceBackend}
+	case KeychainBackendFile:
+		backends = []keyring.BackendType{keyring.FileBackend}
+	}
+
+	// nolint:wrapcheck
+	return keyring.Open(keyring.Config{
+		AllowedBackends: backends,
+		ServiceName:     KeychainServiceName,
+		FileDir:         filepath.Join(machine.HomeDirectory(), ConfigDirectoryName),
 		FilePasswordFunc: func(s string) (string, error) {
 			if keychainPassphrase == "" && !interactive {
 				return "", errors.New("keychain passphrase is required when not running in interactive mode; use the \"--keychain-passphrase\" flag")
@@ -91,30 +184,82 @@ func newKeychain(machine machine.Machine, logger log.Logger, interactive bool) k
 
 			return password, nil
 		},
-	}))
+	})
+}
 
-	return keychain.New(keychain.Args{Keyring: ring})
+// validateKeychainBackend returns an error if the specified keychain backend is not supported.
+func validateKeychainBackend(backend string) error {
+	switch backend {
+	case "", KeychainBackendKeychain, KeychainBackendSecretService, KeychainBackendFile, KeychainBackendAge, KeychainBackendMemory:
+		return nil
+	default:
+		return fmt.Errorf("invalid keychain backend %q", backend)
+	}
 }
 
-// initWithCommand initializes the dependencies of the command.
-func initWithCommand(cmd *cobra.Command) {
+// newRateLimiter returns the limiter shared by the transfers of the process, or nil when transfers are
+// neither limited nor paused.
+func newRateLimiter(rateValue, scheduleValue string) (ratelimit.Limiter, error) {
+	if rateValue == "" && scheduleValue == "" {
+		return nil, nil
+	}
+
+	var (
+		args ratelimit.Args
+		err  error
+	)
+
+	if rateValue != "" {
+		args.Rate, err = ratelimit.ParseRate(rateValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if scheduleValue != "" {
+		args.Schedule, err = ratelimit.ParseSchedule(scheduleValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ratelimit.New(args), nil
+}
+
+// initWithCommand initializes the dependencies of the command. The logger is initialized even when an
+// error is returned, so that the error can be reported.
+func initWithCommand(cmd *cobra.Command) error {
 	verbose := cmd.Flag("verbose").Value.String() == "true"
 	interactive, _ := cmd.Context().Value(interactiveKey).(bool)
-	format := util.Must(OutputFormatFromString(cmd.Flag("format").Value.String()))
+	formatValue := cmd.Flag("format").Value.String()
+	format := util.Must(OutputFormatFromString(formatValue))
 
-	dependencies.Logger = newLogger(format, verbose)
+	dependencies.Logger = newLogger(format, strings.TrimPrefix(formatValue, goTemplatePrefix), verbose)
 	dependencies.OS = operatingsystem.New()
 	dependencies.Machine = machine.New(machine.Args{OS: dependencies.OS})
 	dependencies.CookieJar = newCookieJar(dependencies.Machine)
-	dependencies.Keychain = newKeychain(dependencies.Machine, dependencies.Logger, interactive)
+	chain, err := newKeychain(dependencies.Machine, dependencies.Logger, interactive, keychainBackend)
+	if err != nil {
+		return err
+	}
+
+	dependencies.Keychain = chain
 	dependencies.AppStore = appstore.NewAppStore(appstore.Args{
 		CookieJar:       dependencies.CookieJar,
 		OperatingSystem: dependencies.OS,
 		Keychain:        dependencies.Keychain,
 		Machine:         dependencies.Machine,
+		Limiter:         rateLimiter,
+	})
+	// The output of the hooks goes to stderr, so that it is not mixed with the result of the command.
+	dependencies.Hooks = hook.New(hook.Args{
+		Commands: postDownloadHooks,
+		Output:   os.Stderr,
 	})
 
 	util.Must("", createConfigDirectory(dependencies.OS, dependencies.Machine))
+
+	return nil
 }
 
 // createConfigDirectory creates the configuration directory for the CLI tool, if needed.
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/majd/ipatool/v2/pkg/util/machine"
+	"github.com/majd/ipatool/v2/pkg/util/operatingsystem"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("newKeychain", func() {
+	var mach machine.Machine
+
+	BeforeEach(func() {
+		mach = machine.New(machine.Args{OS: operatingsystem.New()})
+
+		DeferCleanup(func(identity string, recipients []string) {
+			keychainAgeIdentity, keychainAgeRecipients = identity, recipients
+		}, keychainAgeIdentity, keychainAgeRecipients)
+	})
+
+	When("the age backend has neither an identity nor a recipient", func() {
+		BeforeEach(func() {
+			keychainAgeIdentity, keychainAgeRecipients = "", nil
+		})
+
+		It("returns error", func() {
+			chain, err := newKeychain(mach, nil, false, KeychainBackendAge)
+			Expect(err).To(MatchError(ContainSubstring("either an age identity file or a recipient must be specified")))
+			Expect(chain).To(BeNil())
+		})
+	})
+
+	When("the age identity file cannot be read", func() {
+		BeforeEach(func() {
+			keychainAgeIdentity = filepath.Join(GinkgoT().TempDir(), "missing.txt")
+		})
+
+		It("returns error", func() {
+			_, err := newKeychain(mach, nil, false, KeychainBackendAge)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
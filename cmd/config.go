@@ -0,0 +1,386 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/majd/ipatool/v2/pkg/config"
+	"github.com/majd/ipatool/v2/pkg/keychain"
+	"github.com/majd/ipatool/v2/pkg/util/machine"
+	"github.com/majd/ipatool/v2/pkg/util/operatingsystem"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// environmentPrefix prefixes the names of the environment variables bound to flags, e.g. IPATOOL_FORMAT.
+const environmentPrefix = "IPATOOL_"
+
+// configFlagName is the name of the flag specifying the path of the configuration file.
+const configFlagName = "config"
+
+// nolint:wrapcheck
+func configCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the default values of flags stored in the configuration file",
+		Long: "Manage the default values of flags stored in the configuration file. Keys are flag names, e.g. 'format', " +
+			"which apply to every command with the flag, optionally prefixed with a command path, e.g. 'download.output', " +
+			"which apply to that command only.",
+	}
+
+	cmd.AddCommand(configGetCmd())
+	cmd.AddCommand(configSetCmd())
+	cmd.AddCommand(configUnsetCmd())
+	cmd.AddCommand(configListCmd())
+
+	return cmd
+}
+
+// nolint:wrapcheck
+func configGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print a value from the configuration file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value, ok := dependencies.Config.Get(args[0])
+			if !ok {
+				return fmt.Errorf("key %q is not set in %s", args[0], dependencies.Config.Path())
+			}
+
+			logResult(configEntry{
+				Key:   args[0],
+				Value: value,
+			})
+
+			return nil
+		},
+	}
+}
+
+// nolint:wrapcheck
+func configSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Store a value in the configuration file",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, value := args[0], args[1]
+
+			flags := configKeyFlags(cmd.Root(), key)
+			if len(flags) == 0 {
+				return fmt.Errorf("unknown key %q; keys must be a flag name optionally prefixed with a command path, e.g. 'download.output'", key)
+			}
+
+			for _, flag := range flags {
+				err := validateFlagValue(flag, value)
+				if err != nil {
+					return fmt.Errorf("invalid value %q for key %q: %w", value, key, err)
+				}
+			}
+
+			err := dependencies.Config.Set(key, value)
+			if err != nil {
+				return err
+			}
+
+			logResult(configSetResult{
+				Key:   key,
+				Value: value,
+				Path:  dependencies.Config.Path(),
+			})
+
+			return nil
+		},
+	}
+}
+
+// nolint:wrapcheck
+func configUnsetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove a value from the configuration file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, ok := dependencies.Config.Get(args[0])
+			if !ok {
+				return fmt.Errorf("key %q is not set in %s", args[0], dependencies.Config.Path())
+			}
+
+			err := dependencies.Config.Unset(args[0])
+			if err != nil {
+				return err
+			}
+
+			logResult(configUnsetResult{
+				Key:  args[0],
+				Path: dependencies.Config.Path(),
+			})
+
+			return nil
+		},
+	}
+}
+
+// nolint:wrapcheck
+func configListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the values stored in the configuration file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keys := dependencies.Config.Keys()
+			entries := make([]configEntry, 0, len(keys))
+
+			for _, key := range keys {
+				value, _ := dependencies.Config.Get(key)
+				entries = append(entries, configEntry{
+					Key:   key,
+					Value: value,
+				})
+			}
+
+			logResult(configListResult{
+				Path:    dependencies.Config.Path(),
+				Count:   len(entries),
+				Entries: entries,
+			})
+
+			return nil
+		},
+	}
+}
+
+// loadConfig loads the configuration file specified with the config flag or, if not specified, the
+// default configuration file.
+func loadConfig(cmd *cobra.Command) (config.Config, error) {
+	path := cmd.Flag(configFlagName).Value.String()
+	if path == "" {
+		home := machine.New(machine.Args{OS: operatingsystem.New()}).HomeDirectory()
+		path = filepath.Join(home, ConfigDirectoryName, ConfigFileName)
+	}
+
+	cfg, err := config.New(config.Args{Path: path})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// applyConfig sets the flags of the command which were not specified on the command line from the
+// environment or, failing that, from the configuration file. Values scoped to the command take precedence
+// over global values, e.g. IPATOOL_DOWNLOAD_OUTPUT over IPATOOL_OUTPUT and 'download.output' over 'output'.
+func applyConfig(cmd *cobra.Command) (config.Config, error) {
+	err := applyEnvironment(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	var applyErr error
+
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if applyErr != nil || flag.Changed || !bindable(flag) || flag.Name == configFlagName {
+			return
+		}
+
+		for _, key := range configKeys(cmd, flag) {
+			values, ok := cfg.GetList(key)
+			if !ok {
+				continue
+			}
+
+			// The items of a sequence are set one by one, so that flags which do not split their values,
+			// e.g. the hooks, receive each item separately.
+			if _, isSlice := flag.Value.(pflag.SliceValue); !isSlice {
+				values = []string{strings.Join(values, ",")}
+			}
+
+			for _, value := range values {
+				err := cmd.Flags().Set(flag.Name, value)
+				if err != nil {
+					applyErr = fmt.Errorf("invalid value %q for key %q in %s: %w", value, key, cfg.Path(), err)
+
+					return
+				}
+			}
+
+			return
+		}
+	})
+
+	return cfg, applyErr
+}
+
+// applyEnvironment sets the flags of the command which were not specified on the command line from
+// the environment.
+func applyEnvironment(cmd *cobra.Command) error {
+	var applyErr error
+
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if applyErr != nil || flag.Changed || !bindable(flag) {
+			return
+		}
+
+		for _, name := range environmentNames(cmd, flag) {
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				continue
+			}
+
+			err := cmd.Flags().Set(flag.Name, value)
+			if err != nil {
+				applyErr = fmt.Errorf("invalid value %q for environment variable %s: %w", value, name, err)
+			}
+
+			return
+		}
+	})
+
+	return applyErr
+}
+
+// bindable reports whether the flag can be set from the environment or the configuration file.
+func bindable(flag *pflag.Flag) bool {
+	return flag.Name != "help" && flag.Name != "version"
+}
+
+// commandPath returns the names of the command and its parents, excluding the root command.
+func commandPath(cmd *cobra.Command) []string {
+	var names []string
+
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		names = append([]string{c.Name()}, names...)
+	}
+
+	return names
+}
+
+// environmentNames returns the names of the environment variables bound to the flag, in order of precedence.
+func environmentNames(cmd *cobra.Command, flag *pflag.Flag) []string {
+	name := func(parts ...string) string {
+		value := strings.ToUpper(strings.Join(parts, "_"))
+
+		return environmentPrefix + strings.ReplaceAll(value, "-", "_")
+	}
+
+	path := commandPath(cmd)
+	if len(path) == 0 {
+		return []string{name(flag.Name)}
+	}
+
+	return []string{name(append(path, flag.Name)...), name(flag.Name)}
+}
+
+// configKeys returns the keys of the configuration file bound to the flag, in order of precedence.
+func configKeys(cmd *cobra.Command, flag *pflag.Flag) []string {
+	path := commandPath(cmd)
+	if len(path) == 0 {
+		return []string{flag.Name}
+	}
+
+	return []string{strings.Join(append(path, flag.Name), "."), flag.Name}
+}
+
+// configKeyFlags returns the flags bound to the configuration key in the command tree.
+func configKeyFlags(root *cobra.Command, key string) []*pflag.Flag {
+	var flags []*pflag.Flag
+
+	walkCommands(root, func(cmd *cobra.Command) {
+		visitCommandFlags(cmd, func(flag *pflag.Flag) {
+			if bindable(flag) && flag.Name != configFlagName && containsString(configKeys(cmd, flag), key) {
+				flags = append(flags, flag)
+			}
+		})
+	})
+
+	return flags
+}
+
+// flagEnvironmentNames returns the names of all the environment variables bound to flags in the command tree.
+func flagEnvironmentNames(root *cobra.Command) map[string]bool {
+	names := map[string]bool{}
+
+	walkCommands(root, func(cmd *cobra.Command) {
+		visitCommandFlags(cmd, func(flag *pflag.Flag) {
+			for _, name := range environmentNames(cmd, flag) {
+				names[name] = true
+			}
+		})
+	})
+
+	return names
+}
+
+// keychainEnvironment returns the environment variables seeding the memory keyring. Variables bound to
+// flags, e.g. IPATOOL_KEYCHAIN_PASSPHRASE, share the prefix of the keyring items and are excluded.
+func keychainEnvironment(root *cobra.Command) []string {
+	bound := flagEnvironmentNames(root)
+
+	var environ []string
+
+	for _, variable := range os.Environ() {
+		name, _, _ := strings.Cut(variable, "=")
+		if strings.HasPrefix(name, keychain.EnvironmentItemPrefix) && !bound[name] {
+			environ = append(environ, variable)
+		}
+	}
+
+	return environ
+}
+
+// validateFlagValue returns an error if the value cannot be parsed as a value of the flag.
+func validateFlagValue(flag *pflag.Flag, value string) error {
+	fs := pflag.NewFlagSet(flag.Name, pflag.ContinueOnError)
+
+	switch flag.Value.Type() {
+	case "bool":
+		fs.Bool(flag.Name, false, "")
+	case "int":
+		fs.Int(flag.Name, 0, "")
+	case "int64":
+		fs.Int64(flag.Name, 0, "")
+	case "int64Slice":
+		fs.Int64Slice(flag.Name, nil, "")
+	case "float64":
+		fs.Float64(flag.Name, 0, "")
+	case "duration":
+		fs.Duration(flag.Name, 0, "")
+	case "format":
+		fs.Var(&outputFormatValue{}, flag.Name, "")
+	default:
+		return nil
+	}
+
+	return fs.Set(flag.Name, value) // nolint:wrapcheck
+}
+
+func walkCommands(cmd *cobra.Command, fn func(cmd *cobra.Command)) {
+	fn(cmd)
+
+	for _, child := range cmd.Commands() {
+		walkCommands(child, fn)
+	}
+}
+
+// visitCommandFlags visits the local and inherited flags of the command.
+func visitCommandFlags(cmd *cobra.Command, fn func(flag *pflag.Flag)) {
+	cmd.LocalFlags().VisitAll(fn)
+	cmd.InheritedFlags().VisitAll(fn)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
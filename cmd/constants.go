@@ -4,4 +4,22 @@ const (
 	ConfigDirectoryName = ".ipatool"
 	CookieJarFileName   = "cookies"
 	KeychainServiceName = "ipatool-auth.service"
+	AgeKeychainFileName = "keychain.age"
+	ConfigFileName      = "config.yaml"
+	JobsFileName        = "jobs.json"
+	// LibraryDirectoryName is the directory of the library, relative to the configuration directory.
+	LibraryDirectoryName = "library"
+	// LibraryStagingDirectoryName is the directory packages are downloaded to before they are added to
+	// the library, relative to the library.
+	LibraryStagingDirectoryName = "staging"
+	// RejectedPackageSuffix is appended to the path of app packages rejected by a post-download hook.
+	RejectedPackageSuffix = ".rejected"
+)
+
+const (
+	KeychainBackendKeychain      = "keychain"
+	KeychainBackendSecretService = "secret-service"
+	KeychainBackendFile          = "file"
+	KeychainBackendAge           = "age"
+	KeychainBackendMemory        = "memory"
 )
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/majd/ipatool/v2/pkg/schema"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+)
+
+// nolint:wrapcheck
+func developerCmd() *cobra.Command {
+	var (
+		sellerName    string
+		platformValue string
+		country       string
+		fields        []string
+		purchase      bool
+		download      bool
+		outputPath    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "developer [<artist-id>]",
+		Short: "List the iOS and tvOS apps published by a developer on the App Store",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := appstore.ValidateAppFields(fields)
+			if err != nil {
+				return err
+			}
+
+			var artistID int64
+
+			if len(args) == 1 {
+				artistID, err = strconv.ParseInt(args[0], 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid artist ID %q", args[0])
+				}
+			}
+
+			if artistID == 0 && sellerName == "" {
+				return errors.New("either the artist ID or the seller name must be specified")
+			}
+
+			if download && outputPath != "" {
+				info, err := dependencies.OS.Stat(outputPath)
+				if err != nil || !info.IsDir() {
+					return fmt.Errorf("output path %q must be an existing directory", outputPath)
+				}
+			}
+
+			platform, err := appstore.ParsePlatform(platformValue)
+			if err != nil {
+				return err
+			}
+
+			var acc appstore.Account
+
+			if country == "" || purchase || download {
+				infoResult, err := dependencies.AppStore.AccountInfo()
+				if err != nil {
+					return err
+				}
+
+				acc = infoResult.Account
+			}
+
+			output, err := dependencies.AppStore.DeveloperApps(appstore.DeveloperAppsInput{
+				Account:    acc,
+				ArtistID:   artistID,
+				SellerName: sellerName,
+				Platform:   platform,
+				Country:    country,
+			})
+			if err != nil {
+				return err
+			}
+
+			result := developerResult{
+				ArtistID: output.ArtistID,
+				Count:    len(output.Apps),
+				Apps:     appstore.Apps(output.Apps).WithFields(fields),
+			}
+
+			if purchase || download {
+				interactive, _ := cmd.Context().Value(interactiveKey).(bool)
+
+				result.Results = processDeveloperApps(output.Apps, developerOptions{
+					purchase:    purchase,
+					download:    download,
+					outputPath:  outputPath,
+					platform:    platform,
+					interactive: interactive,
+				})
+			}
+
+			logResult(result)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&sellerName, "seller-name", "", "Name of the seller to resolve the artist ID from when it is not specified")
+	cmd.Flags().StringVar(&platformValue, "platform", "", "Platform to list apps for: iphone, ipad, or appletv (defaults to all platforms)")
+	cmd.Flags().StringVar(&country, "country", "", "Two-letter code of the country to list apps in (defaults to the account's country)")
+	cmd.Flags().StringSliceVar(&fields, "fields", nil, "App fields to include in the output (defaults to all fields)")
+	cmd.Flags().BoolVar(&purchase, "purchase", false, "Obtain a license for each of the developer's free apps")
+	cmd.Flags().BoolVar(&download, "download", false, "Download each of the developer's apps")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "The directory to download the app packages to")
+
+	return cmd
+}
+
+type developerOptions struct {
+	purchase    bool
+	download    bool
+	outputPath  string
+	platform    appstore.Platform
+	interactive bool
+}
+
+type developerAppResult struct {
+	app          appstore.App
+	status       string
+	alreadyOwned bool
+	output       string
+	err          error
+}
+
+func (r developerAppResult) MarshalZerologObject(event *zerolog.Event) {
+	event.
+		Int64("id", r.app.ID).
+		Str("bundleID", r.app.BundleID).
+		Str("status", r.status)
+
+	if r.alreadyOwned {
+		event.Bool("alreadyOwned", true)
+	}
+
+	if r.output != "" {
+		event.Str("output", r.output)
+	}
+
+	if r.err != nil {
+		event.
+			Str("error", r.err.Error()).
+			Str("errorCode", string(appstore.ErrorCodeOf(r.err)))
+	}
+}
+
+type developerAppResults []developerAppResult
+
+func (r developerAppResults) JSONSchema() *schema.Schema {
+	return schema.ArrayOf(&schema.Schema{
+		Type: "object",
+		Properties: map[string]*schema.Schema{
+			"id":           schema.Of("integer"),
+			"bundleID":     schema.Of("string"),
+			"status":       {Type: "string", Enum: []interface{}{"succeeded", "failed", "skipped"}},
+			"alreadyOwned": schema.Of("boolean"),
+			"output":       schema.Of("string"),
+			"error":        schema.Of("string"),
+			"errorCode":    schema.Of("string"),
+		},
+		Required: []string{"id", "bundleID", "status"},
+	})
+}
+
+func (r developerAppResults) MarshalZerologArray(arr *zerolog.Array) {
+	for _, result := range r {
+		arr.Object(result)
+	}
+}
+
+// processDeveloperApps purchases and/or downloads each of the apps in turn. A failure for one app is
+// recorded in its result and does not prevent the remaining apps from being processed. Only free apps
+// are purchased.
+func processDeveloperApps(apps []appstore.App, opts developerOptions) developerAppResults {
+	results := make(developerAppResults, 0, len(apps))
+
+	for _, app := range apps {
+		result := developerAppResult{app: app}
+		free := app.Price == 0
+
+		switch {
+		case opts.download:
+			out, err := downloadApp(downloadOptions{
+				appID:          app.ID,
+				outputPath:     opts.outputPath,
+				platform:       opts.platform,
+				acquireLicense: opts.purchase && free,
+				progress:       newProgressBar(opts.interactive),
+			})
+			result.output = out.destinationPath
+			result.err = err
+		case free:
+			result.alreadyOwned, result.err = purchaseApp(app, "")
+		default:
+			result.status = "skipped"
+			results = append(results, result)
+
+			continue
+		}
+
+		if result.err != nil {
+			result.status = "failed"
+
+			dependencies.Logger.Verbose().
+				Err(result.err).
+				Int64("appID", app.ID).
+				Msg("failed to process app")
+		} else {
+			result.status = "succeeded"
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
@@ -1,14 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"errors"
-	"fmt"
-	"os"
+	"text/template"
 	"time"
 
 	"github.com/avast/retry-go"
 	"github.com/majd/ipatool/v2/pkg/appstore"
-	"github.com/schollz/progressbar/v3"
+	"github.com/majd/ipatool/v2/pkg/hook"
+	"github.com/majd/ipatool/v2/pkg/library"
+	"github.com/majd/ipatool/v2/pkg/progress"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +23,10 @@ func downloadCmd() *cobra.Command {
 		bundleID          string
 		externalVersionID string
 		platformValue     string
+		useLibrary        bool
+		nameTemplate      string
+		ifExistsValue     string
+		progressOptions   progressFlags
 	)
 
 	cmd := &cobra.Command{
@@ -31,128 +37,67 @@ func downloadCmd() *cobra.Command {
 				return errors.New("either the app ID or the bundle identifier must be specified")
 			}
 
-			var lastErr error
-			var acc appstore.Account
-			purchased := false
-
-			return retry.Do(func() error {
-				infoResult, err := dependencies.AppStore.AccountInfo()
-				if err != nil {
-					return err
-				}
-
-				acc = infoResult.Account
+			platform, err := appstore.ParsePlatform(platformValue)
+			if err != nil {
+				return err
+			}
 
-				if errors.Is(lastErr, appstore.ErrPasswordTokenExpired) {
-					bagOutput, err := dependencies.AppStore.Bag(appstore.BagInput{})
-					if err != nil {
-						return fmt.Errorf("failed to get bag: %w", err)
-					}
+			ifExists, err := appstore.ParseExistsPolicy(ifExistsValue)
+			if err != nil {
+				return err
+			}
 
-					loginResult, err := dependencies.AppStore.Login(appstore.LoginInput{
-						Email:    acc.Email,
-						Password: acc.Password,
-						Endpoint: bagOutput.AuthEndpoint,
-					})
-					if err != nil {
-						return err
-					}
+			if useLibrary && cmd.Flags().Changed("if-exists") {
+				return errors.New("the if-exists policy cannot be used when downloading to the library")
+			}
 
-					acc = loginResult.Account
+			var fileNameTemplate *template.Template
+			if nameTemplate != "" {
+				if useLibrary {
+					return errors.New("the name template cannot be used when downloading to the library")
 				}
 
-				app := appstore.App{ID: appID}
-				platform, err := appstore.ParsePlatform(platformValue)
+				fileNameTemplate, err = appstore.ParseFileNameTemplate(nameTemplate)
 				if err != nil {
 					return err
 				}
+			}
 
-				if bundleID != "" {
-					lookupResult, err := dependencies.AppStore.Lookup(appstore.LookupInput{
-						Account:  acc,
-						BundleID: bundleID,
-						Platform: platform,
-					})
-					if err != nil {
-						return err
-					}
-
-					app = lookupResult.App
-				}
-
-				if errors.Is(lastErr, appstore.ErrLicenseRequired) {
-					err := dependencies.AppStore.Purchase(appstore.PurchaseInput{Account: acc, App: app})
-					if err != nil && !errors.Is(err, appstore.ErrLicenseAlreadyExists) {
-						return err
-					}
-					purchased = true
-					dependencies.Logger.Verbose().
-						Bool("success", true).
-						Msg("purchase")
-				}
+			reporter, err := newDownloadProgress(cmd, progressOptions)
+			if err != nil {
+				return err
+			}
 
-				interactive, _ := cmd.Context().Value(interactiveKey).(bool)
-				var progress *progressbar.ProgressBar
-				if interactive {
-					progress = progressbar.NewOptions64(1,
-						progressbar.OptionSetDescription("downloading"),
-						progressbar.OptionSetWriter(os.Stdout),
-						progressbar.OptionShowBytes(true),
-						progressbar.OptionSetWidth(20),
-						progressbar.OptionFullWidth(),
-						progressbar.OptionThrottle(65*time.Millisecond),
-						progressbar.OptionShowCount(),
-						progressbar.OptionClearOnFinish(),
-						progressbar.OptionSpinnerType(14),
-						progressbar.OptionSetRenderBlankState(true),
-						progressbar.OptionSetElapsedTime(false),
-						progressbar.OptionSetPredictTime(false),
-					)
-				}
+			var lib library.Library
+			if useLibrary {
+				lib = newLibrary(cmd)
+			}
 
-				out, err := dependencies.AppStore.Download(appstore.DownloadInput{
-					Account:           acc,
-					App:               app,
-					OutputPath:        outputPath,
-					Progress:          progress,
-					ExternalVersionID: externalVersionID,
-					Platform:          platform,
-				})
-				if err != nil {
-					return err
-				}
+			out, err := downloadApp(downloadOptions{
+				appID:             appID,
+				bundleID:          bundleID,
+				outputPath:        outputPath,
+				externalVersionID: externalVersionID,
+				platform:          platform,
+				acquireLicense:    acquireLicense,
+				progress:          reporter,
+				ctx:               cmd.Context(),
+				library:           lib,
+				fileNameTemplate:  fileNameTemplate,
+				ifExists:          ifExists,
+			})
+			if err != nil {
+				return err
+			}
 
-				err = dependencies.AppStore.ReplicateSinf(appstore.ReplicateSinfInput{Sinfs: out.Sinfs, PackagePath: out.DestinationPath})
-				if err != nil {
-					return err
-				}
+			logResult(downloadResult{
+				Output:    out.destinationPath,
+				Purchased: out.purchased,
+				Hash:      out.hash,
+				Skipped:   out.skipped,
+			})
 
-				dependencies.Logger.Log().
-					Str("output", out.DestinationPath).
-					Bool("purchased", purchased).
-					Bool("success", true).
-					Send()
-
-				return nil
-			},
-				retry.LastErrorOnly(true),
-				retry.DelayType(retry.FixedDelay),
-				retry.Delay(time.Millisecond),
-				retry.Attempts(3),
-				retry.RetryIf(func(err error) bool {
-					lastErr = err
-
-					if errors.Is(err, appstore.ErrPasswordTokenExpired) {
-						return true
-					}
-
-					if errors.Is(err, appstore.ErrLicenseRequired) && acquireLicense {
-						return true
-					}
-
-					return false
-				}),
-			)
+			return nil
 		},
 	}
 
@@ -162,6 +107,193 @@ func downloadCmd() *cobra.Command {
 	cmd.Flags().StringVar(&externalVersionID, "external-version-id", "", "External version identifier of the target iOS app (defaults to latest version when not specified)")
 	cmd.Flags().StringVar(&platformValue, "platform", "", "Platform to download for: iphone, ipad, or appletv")
 	cmd.Flags().BoolVar(&acquireLicense, "purchase", false, "Obtain a license for the app if needed")
+	cmd.Flags().StringVar(&nameTemplate, "name-template", "", "Go template of the path of the app package within the output directory, e.g. '{{.BundleID}}/{{.Version}}/{{.Name}}.ipa'")
+	cmd.Flags().StringVar(&ifExistsValue, "if-exists", "overwrite", "What to do when the app package already exists: skip, overwrite, rename, or fail")
+	cmd.Flags().BoolVar(&useLibrary, "library", false, "Store the app package in the library instead of the output path, skipping versions already in the library")
+	cmd.Flags().String(libraryPathFlagName, "", "The directory of the library (defaults to ~/.ipatool/library)")
+	progressOptions.register(cmd)
 
 	return cmd
 }
+
+type downloadOptions struct {
+	appID             int64
+	bundleID          string
+	outputPath        string
+	externalVersionID string
+	platform          appstore.Platform
+	acquireLicense    bool
+	// progress reports the progress of the download when set.
+	progress progress.Progress
+	// ctx cancels the download when done. Defaults to a context which is never cancelled.
+	ctx context.Context
+	// library stores the package instead of the output path when set.
+	library library.Library
+	// fileNameTemplate renders the path of the package within the output directory when set.
+	fileNameTemplate *template.Template
+	// ifExists determines what happens when the package already exists at the output path.
+	ifExists appstore.ExistsPolicy
+}
+
+type downloadOutput struct {
+	destinationPath string
+	purchased       bool
+	// hash is the digest of the package in the library, when downloading to the library.
+	hash string
+	// skipped is true when the version was already in the library or the package already existed.
+	skipped bool
+}
+
+// downloadApp downloads the app package and replicates its sinfs, logging in again when the password
+// token expired and obtaining a license first when needed and allowed.
+// nolint:wrapcheck
+func downloadApp(opts downloadOptions) (downloadOutput, error) {
+	var (
+		lastErr   error
+		purchased bool
+		output    downloadOutput
+	)
+
+	if opts.ctx == nil {
+		opts.ctx = context.Background()
+	}
+
+	if opts.progress == nil {
+		opts.progress = progress.Discard
+	}
+
+	err := retry.Do(func() error {
+		acc, err := resolveAccount(lastErr)
+		if err != nil {
+			return err
+		}
+
+		app := appstore.App{ID: opts.appID}
+
+		if opts.bundleID != "" {
+			lookupResult, err := dependencies.AppStore.Lookup(appstore.LookupInput{
+				Account:  acc,
+				BundleID: opts.bundleID,
+				Platform: opts.platform,
+			})
+			if err != nil {
+				return err
+			}
+
+			app = lookupResult.App
+		}
+
+		if errors.Is(lastErr, appstore.ErrLicenseRequired) {
+			err := dependencies.AppStore.Purchase(appstore.PurchaseInput{Account: acc, App: app})
+			if err != nil && !errors.Is(err, appstore.ErrLicenseAlreadyExists) {
+				return err
+			}
+			purchased = true
+			dependencies.Logger.Verbose().
+				Bool("success", true).
+				Msg("purchase")
+		}
+
+		if opts.library != nil {
+			output, err = downloadToLibrary(acc, app, opts)
+			output.purchased = purchased
+
+			return err
+		}
+
+		out, err := dependencies.AppStore.Download(appstore.DownloadInput{
+			Account:           acc,
+			App:               app,
+			OutputPath:        opts.outputPath,
+			Progress:          opts.progress,
+			ExternalVersionID: opts.externalVersionID,
+			Platform:          opts.platform,
+			Context:           opts.ctx,
+			FileNameTemplate:  opts.fileNameTemplate,
+			IfExists:          opts.ifExists,
+		})
+		if err != nil {
+			return err
+		}
+
+		if out.Skipped {
+			opts.progress.SetPhase(progress.PhaseDone)
+
+			output = downloadOutput{
+				destinationPath: out.DestinationPath,
+				purchased:       purchased,
+				skipped:         true,
+			}
+
+			return nil
+		}
+
+		opts.progress.SetPhase(progress.PhaseSinf)
+
+		err = dependencies.AppStore.ReplicateSinf(appstore.ReplicateSinfInput{Sinfs: out.Sinfs, PackagePath: out.DestinationPath})
+		if err != nil {
+			return err
+		}
+
+		err = runPostDownloadHooks(opts.ctx, out.DestinationPath, acc, out)
+		if err != nil {
+			// The package is moved aside, so that the next download does not skip it as an existing
+			// package without running the hooks again.
+			_ = dependencies.OS.Rename(out.DestinationPath, out.DestinationPath+RejectedPackageSuffix)
+
+			return err
+		}
+
+		opts.progress.SetPhase(progress.PhaseDone)
+
+		output = downloadOutput{
+			destinationPath: out.DestinationPath,
+			purchased:       purchased,
+		}
+
+		return nil
+	},
+		retry.Context(opts.ctx),
+		retry.LastErrorOnly(true),
+		retry.DelayType(retry.FixedDelay),
+		retry.Delay(time.Millisecond),
+		retry.Attempts(3),
+		retry.RetryIf(func(err error) bool {
+			lastErr = err
+
+			if errors.Is(err, appstore.ErrPasswordTokenExpired) {
+				return true
+			}
+
+			if errors.Is(err, appstore.ErrLicenseRequired) && opts.acquireLicense {
+				return true
+			}
+
+			return false
+		}),
+	)
+
+	return output, err
+}
+
+// runPostDownloadHooks runs the post-download hooks for the app package at the specified path.
+func runPostDownloadHooks(ctx context.Context, path string, acc appstore.Account, out appstore.DownloadOutput) error {
+	if !dependencies.Hooks.Enabled() {
+		return nil
+	}
+
+	checksums, err := hook.ChecksumsOf(path)
+	if err != nil {
+		return err
+	}
+
+	return dependencies.Hooks.Run(ctx, hook.Event{
+		Path:              path,
+		BundleID:          out.BundleID,
+		Version:           out.Version,
+		ExternalVersionID: out.ExternalVersionID,
+		Checksums:         checksums,
+		SinfCount:         len(out.Sinfs),
+		Account:           acc.Email,
+	})
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/majd/ipatool/v2/pkg/hook"
+)
+
+const (
+	exitCodeSuccess = 0
+	exitCodeFailure = 1
+)
+
+// exitCodes maps error codes to process exit statuses. The values are part of the public interface
+// of the tool and must not change.
+var exitCodes = map[appstore.ErrorCode]int{
+	appstore.ErrorCodeUnknown:                  exitCodeFailure,
+	appstore.ErrorCodeAppStore:                 3,
+	appstore.ErrorCodeLicenseRequired:          10,
+	appstore.ErrorCodeLicenseAlreadyExists:     11,
+	appstore.ErrorCodePasswordTokenExpired:     12,
+	appstore.ErrorCodeAuthCodeRequired:         13,
+	appstore.ErrorCodeInvalidCredentials:       14,
+	appstore.ErrorCodeAccountDisabled:          15,
+	appstore.ErrorCodeDeviceVerificationFailed: 16,
+	appstore.ErrorCodeSubscriptionRequired:     17,
+	appstore.ErrorCodeTemporarilyUnavailable:   18,
+	appstore.ErrorCodeAppNotFound:              19,
+	appstore.ErrorCodeDeveloperNotFound:        20,
+	appstore.ErrorCodeRateLimited:              21,
+	appstore.ErrorCodePackageExists:            22,
+	appstore.ErrorCodeInsufficientDiskSpace:    23,
+	appstore.ErrorCode(hook.ErrorCodeRejected): 24,
+	appstore.ErrorCodeNotAuthenticated:         25,
+}
+
+// exitCodeOf returns the process exit status for the specified error code.
+func exitCodeOf(code appstore.ErrorCode) int {
+	if exitCode, ok := exitCodes[code]; ok {
+		return exitCode
+	}
+
+	return exitCodeFailure
+}
@@ -0,0 +1,336 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"text/template"
+
+	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/majd/ipatool/v2/pkg/jobs"
+	"github.com/majd/ipatool/v2/pkg/progress"
+	"github.com/spf13/cobra"
+)
+
+// nolint:wrapcheck
+func jobsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "Manage the persistent queue of download jobs",
+		Long: "Manage the persistent queue of download jobs. Jobs survive restarts: jobs interrupted by a crash are " +
+			"queued again and resume their partially downloaded package the next time jobs are run.",
+	}
+
+	cmd.AddCommand(jobsAddCmd())
+	cmd.AddCommand(jobsListCmd())
+	cmd.AddCommand(jobsCancelCmd())
+	cmd.AddCommand(jobsRetryCmd())
+	cmd.AddCommand(jobsRunCmd())
+
+	return cmd
+}
+
+// nolint:wrapcheck
+func jobsAddCmd() *cobra.Command {
+	var (
+		acquireLicense    bool
+		outputPath        string
+		appID             int64
+		bundleID          string
+		externalVersionID string
+		platformValue     string
+		nameTemplate      string
+		ifExistsValue     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Queue a job downloading an app package",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if appID == 0 && bundleID == "" {
+				return errors.New("either the app ID or the bundle identifier must be specified")
+			}
+
+			_, err := appstore.ParsePlatform(platformValue)
+			if err != nil {
+				return err
+			}
+
+			ifExists, err := appstore.ParseExistsPolicy(ifExistsValue)
+			if err != nil {
+				return err
+			}
+
+			if nameTemplate != "" {
+				_, err = appstore.ParseFileNameTemplate(nameTemplate)
+				if err != nil {
+					return err
+				}
+			}
+
+			// The jobs may be run from another directory.
+			outputPath, err = filepath.Abs(outputPath)
+			if err != nil {
+				return fmt.Errorf("failed to resolve output path: %w", err)
+			}
+
+			info, err := dependencies.AppStore.AccountInfo()
+			if err != nil {
+				return err
+			}
+
+			job, err := newJobQueue(1).Enqueue(jobs.Request{
+				AppID:             appID,
+				BundleID:          bundleID,
+				ExternalVersionID: externalVersionID,
+				Platform:          platformValue,
+				Purchase:          acquireLicense,
+				OutputPath:        outputPath,
+				NameTemplate:      nameTemplate,
+				IfExists:          string(ifExists),
+				Account:           info.Account.Email,
+			})
+			if err != nil {
+				return err
+			}
+
+			logResult(job)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64VarP(&appID, "app-id", "i", 0, "ID of the target iOS app (required)")
+	cmd.Flags().StringVarP(&bundleID, "bundle-identifier", "b", "", "The bundle identifier of the target iOS app (overrides the app ID)")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "The destination path of the downloaded app package")
+	cmd.Flags().StringVar(&externalVersionID, "external-version-id", "", "External version identifier of the target iOS app (defaults to latest version when not specified)")
+	cmd.Flags().StringVar(&platformValue, "platform", "", "Platform to download for: iphone, ipad, or appletv")
+	cmd.Flags().BoolVar(&acquireLicense, "purchase", false, "Obtain a license for the app if needed")
+	cmd.Flags().StringVar(&ifExistsValue, "if-exists", "overwrite", "What to do when the app package already exists: skip, overwrite, rename, or fail")
+	cmd.Flags().StringVar(&nameTemplate, "name-template", "", "Go template of the path of the app package within the output directory, e.g. '{{.BundleID}}/{{.Version}}/{{.Name}}.ipa'")
+
+	return cmd
+}
+
+// nolint:wrapcheck
+func jobsListCmd() *cobra.Command {
+	var statuses []string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the download jobs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			all, err := newJobQueue(1).List()
+			if err != nil {
+				return err
+			}
+
+			result := make([]jobs.Job, 0, len(all))
+
+			for _, job := range all {
+				if len(statuses) == 0 || containsString(statuses, string(job.Status)) {
+					result = append(result, job)
+				}
+			}
+
+			logResult(jobsListResult{
+				Count: len(result),
+				Jobs:  result,
+			})
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&statuses, "status", nil, "Only list the jobs with these statuses: queued, running, succeeded, failed or cancelled")
+
+	return cmd
+}
+
+// nolint:wrapcheck
+func jobsCancelCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel <id>",
+		Short: "Cancel a queued or running download job",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			job, err := newJobQueue(1).Cancel(args[0])
+			if err != nil {
+				return err
+			}
+
+			logResult(job)
+
+			return nil
+		},
+	}
+}
+
+// nolint:wrapcheck
+func jobsRetryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "retry <id>",
+		Short: "Queue a failed or cancelled download job again",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			job, err := newJobQueue(1).Retry(args[0])
+			if err != nil {
+				return err
+			}
+
+			logResult(job)
+
+			return nil
+		},
+	}
+}
+
+// nolint:wrapcheck
+func jobsRunCmd() *cobra.Command {
+	var (
+		concurrency     int
+		progressOptions progressFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run the queued download jobs until none is left",
+		Long: "Run the queued download jobs until none is left. Interrupting the command queues the running jobs " +
+			"again, so that the next run resumes them.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if concurrency < 1 {
+				return errors.New("concurrency must be at least 1")
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			writer, err := progressOptions.jsonWriter()
+			if err != nil {
+				return err
+			}
+
+			runner := &jobRunner{progress: writer}
+
+			queue := jobs.New(jobs.Args{
+				Path:        jobsPath(),
+				Runner:      runner,
+				Concurrency: concurrency,
+			})
+
+			err = queue.RunPending(ctx)
+			if err != nil {
+				return err
+			}
+
+			result := jobsListResult{Jobs: []jobs.Job{}}
+
+			for _, id := range runner.ran {
+				job, err := queue.Get(id)
+				if err != nil {
+					return err
+				}
+
+				result.Jobs = append(result.Jobs, job)
+			}
+
+			result.Count = len(result.Jobs)
+			logResult(result)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&concurrency, "concurrency", 1, "The maximum number of jobs running at once")
+	progressOptions.register(cmd)
+
+	return cmd
+}
+
+func jobsPath() string {
+	return filepath.Join(dependencies.Machine.HomeDirectory(), ConfigDirectoryName, JobsFileName)
+}
+
+func newJobQueue(concurrency int) jobs.Queue {
+	return jobs.New(jobs.Args{
+		Path:        jobsPath(),
+		Runner:      &jobRunner{},
+		Concurrency: concurrency,
+	})
+}
+
+// jobRunner downloads app packages for the download jobs, never prompting for input. It records the
+// IDs of the jobs it ran.
+type jobRunner struct {
+	// progress writes the progress events of the jobs, identified by the job IDs, when set.
+	progress *progress.JSONWriter
+	mutex    sync.Mutex
+	ran      []string
+}
+
+// nolint:wrapcheck
+func (r *jobRunner) Run(ctx context.Context, job jobs.Job) (jobs.Result, error) {
+	r.mutex.Lock()
+	if !containsString(r.ran, job.ID) {
+		r.ran = append(r.ran, job.ID)
+	}
+	r.mutex.Unlock()
+
+	platform, err := appstore.ParsePlatform(job.Request.Platform)
+	if err != nil {
+		return jobs.Result{}, err
+	}
+
+	ifExists, err := appstore.ParseExistsPolicy(job.Request.IfExists)
+	if err != nil {
+		return jobs.Result{}, err
+	}
+
+	var fileNameTemplate *template.Template
+	if job.Request.NameTemplate != "" {
+		fileNameTemplate, err = appstore.ParseFileNameTemplate(job.Request.NameTemplate)
+		if err != nil {
+			return jobs.Result{}, err
+		}
+	}
+
+	info, err := dependencies.AppStore.AccountInfo()
+	if err != nil {
+		return jobs.Result{}, err
+	}
+
+	if job.Request.Account != "" && info.Account.Email != job.Request.Account {
+		return jobs.Result{}, fmt.Errorf("job was created with account %s, but %s is logged in", job.Request.Account, info.Account.Email)
+	}
+
+	var reporter progress.Progress
+	if r.progress != nil {
+		reporter = r.progress.Progress(job.ID)
+	}
+
+	out, err := downloadApp(downloadOptions{
+		appID:             job.Request.AppID,
+		bundleID:          job.Request.BundleID,
+		outputPath:        job.Request.OutputPath,
+		externalVersionID: job.Request.ExternalVersionID,
+		platform:          platform,
+		acquireLicense:    job.Request.Purchase,
+		ctx:               ctx,
+		fileNameTemplate:  fileNameTemplate,
+		ifExists:          ifExists,
+		progress:          reporter,
+	})
+	if err != nil {
+		return jobs.Result{}, err
+	}
+
+	return jobs.Result{
+		Output:    out.destinationPath,
+		Purchased: out.purchased,
+	}, nil
+}
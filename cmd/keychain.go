@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func keychainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keychain",
+		Short: "Manage the keychain holding the App Store credentials",
+	}
+
+	cmd.AddCommand(migrateCmd())
+
+	return cmd
+}
+
+// nolint:wrapcheck
+func migrateCmd() *cobra.Command {
+	var (
+		from         string
+		to           string
+		removeSource bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Copy all items from one keychain backend to another",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, backend := range []string{from, to} {
+				err := validateKeychainBackend(backend)
+				if err != nil {
+					return err
+				}
+			}
+
+			if from == to {
+				return errors.New("the source and destination backends must be different")
+			}
+
+			if to == KeychainBackendMemory {
+				return errors.New("the memory backend cannot be used as a migration destination")
+			}
+
+			interactive, _ := cmd.Context().Value(interactiveKey).(bool)
+
+			source, err := newKeychain(dependencies.Machine, dependencies.Logger, interactive, from)
+			if err != nil {
+				return err
+			}
+
+			destination, err := newKeychain(dependencies.Machine, dependencies.Logger, interactive, to)
+			if err != nil {
+				return err
+			}
+
+			keys, err := source.Keys()
+			if err != nil {
+				return fmt.Errorf("failed to list source items: %w", err)
+			}
+
+			for _, key := range keys {
+				data, err := source.Get(key)
+				if err != nil {
+					return fmt.Errorf("failed to read item '%s': %w", key, err)
+				}
+
+				err = destination.Set(key, data)
+				if err != nil {
+					return fmt.Errorf("failed to write item '%s': %w", key, err)
+				}
+
+				copied, err := destination.Get(key)
+				if err != nil {
+					return fmt.Errorf("failed to verify item '%s': %w", key, err)
+				}
+
+				if !bytes.Equal(copied, data) {
+					return fmt.Errorf("item '%s' does not match the source after copying", key)
+				}
+
+				dependencies.Logger.Verbose().
+					Str("key", key).
+					Msg("migrated item")
+			}
+
+			if removeSource {
+				for _, key := range keys {
+					err := source.Remove(key)
+					if err != nil {
+						return fmt.Errorf("failed to remove source item '%s': %w", key, err)
+					}
+				}
+			}
+
+			logResult(keychainMigrateResult{
+				Keys:          keys,
+				From:          from,
+				To:            to,
+				SourceRemoved: removeSource,
+			})
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "The backend to copy items from; can be 'keychain', 'secret-service', 'file', 'age', 'memory' (required)")
+	cmd.Flags().StringVar(&to, "to", "", "The backend to copy items to; can be 'keychain', 'secret-service', 'file', 'age' (required)")
+	cmd.Flags().BoolVar(&removeSource, "remove-source", false, "Remove the items from the source backend once they have been copied")
+
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/majd/ipatool/v2/pkg/library"
+	"github.com/majd/ipatool/v2/pkg/progress"
+	"github.com/spf13/cobra"
+)
+
+// libraryPathFlagName is the name of the flag specifying the directory of the library.
+const libraryPathFlagName = "library-path"
+
+// nolint:wrapcheck
+func libraryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "library",
+		Short: "Manage the local library of app packages",
+		Long: "Manage the local library of app packages. Packages are stored under their SHA-256 digest, so identical " +
+			"packages are stored once, along with an index of their bundle identifier, app ID, versions, platform, " +
+			"account and download time. Packages are referred to by their digest or a prefix of at least 4 characters.",
+	}
+
+	cmd.PersistentFlags().String(libraryPathFlagName, "", "The directory of the library (defaults to ~/.ipatool/library)")
+
+	cmd.AddCommand(libraryAddCmd())
+	cmd.AddCommand(libraryListCmd())
+	cmd.AddCommand(libraryFindCmd())
+	cmd.AddCommand(libraryRemoveCmd())
+	cmd.AddCommand(libraryPathCmd())
+	cmd.AddCommand(libraryGCCmd())
+
+	return cmd
+}
+
+// nolint:wrapcheck
+func libraryAddCmd() *cobra.Command {
+	var (
+		move          bool
+		platformValue string
+		appID         int64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add <path>...",
+		Short: "Add app packages to the library",
+		Long: "Add app packages to the library. The metadata of the packages is read from their iTunesMetadata.plist " +
+			"and Info.plist files. Packages already in the library are reported as duplicates.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			platform, err := appstore.ParsePlatform(platformValue)
+			if err != nil {
+				return err
+			}
+
+			lib := newLibrary(cmd)
+			result := libraryAddResult{Packages: []library.Package{}}
+
+			for _, path := range args {
+				out, err := lib.Add(library.AddInput{
+					Path: path,
+					Metadata: library.Metadata{
+						AppID:    appID,
+						Platform: string(platform),
+					},
+					Move: move,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to add %s: %w", path, err)
+				}
+
+				if out.Duplicate {
+					result.Duplicates++
+				}
+
+				result.Packages = append(result.Packages, out.Package)
+			}
+
+			result.Count = len(result.Packages)
+			logResult(result)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&move, "move", false, "Remove the app packages once they are stored in the library")
+	cmd.Flags().StringVar(&platformValue, "platform", "", "Platform the app packages were downloaded for: iphone, ipad, or appletv")
+	cmd.Flags().Int64VarP(&appID, "app-id", "i", 0, "ID of the app, when the packages lack an iTunesMetadata.plist file")
+
+	return cmd
+}
+
+// nolint:wrapcheck
+func libraryListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the app packages in the library",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			packages, err := newLibrary(cmd).List()
+			if err != nil {
+				return err
+			}
+
+			logResult(libraryListResult{
+				Count:    len(packages),
+				Packages: packages,
+			})
+
+			return nil
+		},
+	}
+}
+
+// nolint:wrapcheck
+func libraryFindCmd() *cobra.Command {
+	var (
+		query         library.Query
+		platformValue string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find the app packages in the library matching all of the specified fields",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			platform, err := appstore.ParsePlatform(platformValue)
+			if err != nil {
+				return err
+			}
+
+			query.Platform = string(platform)
+
+			packages, err := newLibrary(cmd).Find(query)
+			if err != nil {
+				return err
+			}
+
+			logResult(libraryListResult{
+				Count:    len(packages),
+				Packages: packages,
+			})
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64VarP(&query.AppID, "app-id", "i", 0, "ID of the app")
+	cmd.Flags().StringVarP(&query.BundleID, "bundle-identifier", "b", "", "The bundle identifier of the app")
+	cmd.Flags().StringVar(&query.DisplayVersion, "version", "", "The display version of the app, e.g. 1.2.3")
+	cmd.Flags().StringVar(&query.ExternalVersionID, "external-version-id", "", "External version identifier of the app")
+	cmd.Flags().StringVar(&platformValue, "platform", "", "Platform of the app packages: iphone, ipad, or appletv")
+	cmd.Flags().StringVar(&query.Account, "account", "", "Email of the account the app packages were downloaded with")
+
+	return cmd
+}
+
+// nolint:wrapcheck
+func libraryRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <hash>",
+		Short: "Remove an app package from the library",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pkg, err := newLibrary(cmd).Remove(args[0])
+			if err != nil {
+				return err
+			}
+
+			logResult(pkg)
+
+			return nil
+		},
+	}
+}
+
+// nolint:wrapcheck
+func libraryPathCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "path <hash>",
+		Short: "Print the path of an app package in the library",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := newLibrary(cmd).Path(args[0])
+			if err != nil {
+				return err
+			}
+
+			logResult(libraryPathResult{Path: path})
+
+			return nil
+		},
+	}
+}
+
+// nolint:wrapcheck
+func libraryGCCmd() *cobra.Command {
+	var (
+		policyValue string
+		dryRun      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Remove the app packages which are not kept by a retention policy",
+		Long: "Remove the app packages which are not kept by a retention policy. The policy is a comma-separated list " +
+			"of rules, and packages kept by any rule are kept: 'keep-latest=<n>' keeps the latest n versions of each " +
+			"app and platform and 'keep-within=<duration>' keeps the packages downloaded within the duration, e.g. '90d', '12w' " +
+			"or '36h'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if policyValue == "" {
+				return errors.New("a policy is required, e.g. \"--policy keep-latest=5,keep-within=90d\"")
+			}
+
+			policy, err := library.ParsePolicy(policyValue)
+			if err != nil {
+				return err
+			}
+
+			out, err := newLibrary(cmd).GC(library.GCInput{
+				Policy: policy,
+				DryRun: dryRun,
+			})
+			if err != nil {
+				return err
+			}
+
+			logResult(libraryGCResult{
+				DryRun:         dryRun,
+				Count:          len(out.Removed),
+				Kept:           out.Kept,
+				ReclaimedBytes: out.ReclaimedBytes,
+				Removed:        out.Removed,
+			})
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&policyValue, "policy", "", "The retention policy, e.g. keep-latest=5,keep-within=90d (required)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report the app packages which would be removed without removing them")
+
+	return cmd
+}
+
+// newLibrary returns the library in the directory specified by the library path flag of the command.
+func newLibrary(cmd *cobra.Command) library.Library {
+	root := ""
+	if flag := cmd.Flag(libraryPathFlagName); flag != nil {
+		root = flag.Value.String()
+	}
+
+	if root == "" {
+		root = filepath.Join(dependencies.Machine.HomeDirectory(), ConfigDirectoryName, LibraryDirectoryName)
+	}
+
+	return library.New(library.Args{Root: root})
+}
+
+// downloadToLibrary downloads the app package to the staging directory of the library and adds it to the
+// library, unless the version is already in the library.
+// nolint:wrapcheck
+func downloadToLibrary(acc appstore.Account, app appstore.App, opts downloadOptions) (downloadOutput, error) {
+	version := opts.externalVersionID
+
+	// The latest version of tvOS apps is only known once the download starts.
+	if version == "" && opts.platform != appstore.PlatformAppleTV {
+		versions, err := dependencies.AppStore.ListVersions(appstore.ListVersionsInput{Account: acc, App: app})
+		if err != nil {
+			return downloadOutput{}, err
+		}
+
+		version = versions.LatestExternalVersionID
+	}
+
+	if version != "" {
+		existing, err := opts.library.Find(library.Query{AppID: app.ID, ExternalVersionID: version})
+		if err != nil {
+			return downloadOutput{}, err
+		}
+
+		if len(existing) > 0 {
+			path, err := opts.library.Path(existing[0].Hash)
+			if err != nil {
+				return downloadOutput{}, err
+			}
+
+			opts.progress.SetPhase(progress.PhaseDone)
+
+			return downloadOutput{
+				destinationPath: path,
+				hash:            existing[0].Hash,
+				skipped:         true,
+			}, nil
+		}
+	}
+
+	// Partial downloads in the staging directory are resumed by the next download of the version.
+	staging := filepath.Join(opts.library.Root(), LibraryStagingDirectoryName)
+
+	err := dependencies.OS.MkdirAll(staging, 0755)
+	if err != nil {
+		return downloadOutput{}, fmt.Errorf("failed to create library staging directory: %w", err)
+	}
+
+	// The resolved version is downloaded, so that the package matches the version it is stored as, even if
+	// a new version is released in the meantime.
+	out, err := dependencies.AppStore.Download(appstore.DownloadInput{
+		Account:           acc,
+		App:               app,
+		OutputPath:        staging,
+		Progress:          opts.progress,
+		ExternalVersionID: version,
+		Platform:          opts.platform,
+		Context:           opts.ctx,
+	})
+	if err != nil {
+		return downloadOutput{}, err
+	}
+
+	opts.progress.SetPhase(progress.PhaseSinf)
+
+	err = dependencies.AppStore.ReplicateSinf(appstore.ReplicateSinfInput{Sinfs: out.Sinfs, PackagePath: out.DestinationPath})
+	if err != nil {
+		return downloadOutput{}, err
+	}
+
+	// The hooks run on the staged package, so that a rejected package is never added to the library and
+	// hooks cannot modify the packages stored in it.
+	err = runPostDownloadHooks(opts.ctx, out.DestinationPath, acc, out)
+	if err != nil {
+		return downloadOutput{}, err
+	}
+
+	added, err := opts.library.Add(library.AddInput{
+		Path: out.DestinationPath,
+		Metadata: library.Metadata{
+			BundleID:          app.BundleID,
+			AppID:             app.ID,
+			ExternalVersionID: version,
+			Platform:          string(opts.platform),
+			Account:           acc.Email,
+		},
+		Move: true,
+	})
+	if err != nil {
+		return downloadOutput{}, err
+	}
+
+	path, err := opts.library.Path(added.Package.Hash)
+	if err != nil {
+		return downloadOutput{}, err
+	}
+
+	opts.progress.SetPhase(progress.PhaseDone)
+
+	return downloadOutput{
+		destinationPath: path,
+		hash:            added.Package.Hash,
+	}, nil
+}
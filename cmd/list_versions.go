@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/avast/retry-go"
@@ -26,34 +25,13 @@ func ListVersionsCmd() *cobra.Command {
 			}
 
 			var lastErr error
-			var acc appstore.Account
 
 			return retry.Do(func() error {
-				infoResult, err := dependencies.AppStore.AccountInfo()
+				acc, err := resolveAccount(lastErr)
 				if err != nil {
 					return err
 				}
 
-				acc = infoResult.Account
-
-				if errors.Is(lastErr, appstore.ErrPasswordTokenExpired) {
-					bagOutput, err := dependencies.AppStore.Bag(appstore.BagInput{})
-					if err != nil {
-						return fmt.Errorf("failed to get bag: %w", err)
-					}
-
-					loginResult, err := dependencies.AppStore.Login(appstore.LoginInput{
-						Email:    acc.Email,
-						Password: acc.Password,
-						Endpoint: bagOutput.AuthEndpoint,
-					})
-					if err != nil {
-						return err
-					}
-
-					acc = loginResult.Account
-				}
-
 				app := appstore.App{ID: appID}
 				if bundleID != "" {
 					lookupResult, err := dependencies.AppStore.Lookup(appstore.LookupInput{Account: acc, BundleID: bundleID})
@@ -69,11 +47,10 @@ func ListVersionsCmd() *cobra.Command {
 					return err
 				}
 
-				dependencies.Logger.Log().
-					Interface("externalVersionIdentifiers", out.ExternalVersionIdentifiers).
-					Str("bundleID", app.BundleID).
-					Bool("success", true).
-					Send()
+				logResult(listVersionsResult{
+					ExternalVersionIdentifiers: out.ExternalVersionIdentifiers,
+					BundleID:                   app.BundleID,
+				})
 
 				return nil
 			},
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/spf13/cobra"
+)
+
+// nolint:wrapcheck
+func lookupCmd() *cobra.Command {
+	var (
+		appIDs        []int64
+		bundleIDs     []string
+		platformValue string
+		country       string
+		fields        []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lookup [<app-id>|<bundle-identifier>]...",
+		Short: "Look up iOS and tvOS apps on the App Store by app ID or bundle identifier",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := appstore.ValidateAppFields(fields)
+			if err != nil {
+				return err
+			}
+
+			ids := append([]int64{}, appIDs...)
+			bundles := append([]string{}, bundleIDs...)
+
+			// Numeric arguments are treated as app IDs, anything else as a bundle identifier.
+			for _, arg := range args {
+				if id, err := strconv.ParseInt(arg, 10, 64); err == nil {
+					ids = append(ids, id)
+				} else {
+					bundles = append(bundles, arg)
+				}
+			}
+
+			if len(ids) == 0 && len(bundles) == 0 {
+				return errors.New("at least one app ID or bundle identifier must be specified")
+			}
+
+			var acc appstore.Account
+
+			if country == "" {
+				infoResult, err := dependencies.AppStore.AccountInfo()
+				if err != nil {
+					return err
+				}
+
+				acc = infoResult.Account
+			}
+
+			platform, err := appstore.ParsePlatform(platformValue)
+			if err != nil {
+				return err
+			}
+
+			output, err := dependencies.AppStore.LookupBatch(appstore.LookupBatchInput{
+				Account:   acc,
+				AppIDs:    ids,
+				BundleIDs: bundles,
+				Platform:  platform,
+				Country:   country,
+			})
+			if err != nil {
+				return err
+			}
+
+			logResult(lookupResult{
+				Count:             len(output.Apps),
+				Apps:              appstore.Apps(output.Apps).WithFields(fields),
+				NotFoundAppIDs:    output.NotFoundAppIDs,
+				NotFoundBundleIDs: output.NotFoundBundleIDs,
+			})
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64SliceVarP(&appIDs, "app-id", "i", nil, "IDs of the apps to look up")
+	cmd.Flags().StringSliceVarP(&bundleIDs, "bundle-identifier", "b", nil, "Bundle identifiers of the apps to look up")
+	cmd.Flags().StringVar(&platformValue, "platform", "", "Platform to look up: iphone, ipad, or appletv")
+	cmd.Flags().StringVar(&country, "country", "", "Two-letter code of the country to look up in (defaults to the account's country)")
+	cmd.Flags().StringSliceVar(&fields, "fields", nil, "App fields to include in the output (defaults to all fields)")
+
+	return cmd
+}
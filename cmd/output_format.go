@@ -2,24 +2,79 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
-	"github.com/thediveo/enumflag/v2"
+	"github.com/majd/ipatool/v2/pkg/log"
 )
 
-type OutputFormat enumflag.Flag
+type OutputFormat int
 
 const (
 	OutputFormatText OutputFormat = iota
 	OutputFormatJSON
+	OutputFormatTable
+	OutputFormatCSV
+	OutputFormatYAML
+	OutputFormatNDJSON
+	OutputFormatGoTemplate
 )
 
+// goTemplatePrefix prefixes the template of the go-template output format, e.g. 'go-template={{.output}}'.
+const goTemplatePrefix = "go-template="
+
 func OutputFormatFromString(value string) (OutputFormat, error) {
-	switch value {
-	case "json":
+	switch {
+	case value == "json":
 		return OutputFormatJSON, nil
-	case "text":
+	case value == "text":
 		return OutputFormatText, nil
+	case value == "table":
+		return OutputFormatTable, nil
+	case value == "csv":
+		return OutputFormatCSV, nil
+	case value == "yaml":
+		return OutputFormatYAML, nil
+	case value == "ndjson":
+		return OutputFormatNDJSON, nil
+	case strings.HasPrefix(value, goTemplatePrefix):
+		return OutputFormatGoTemplate, nil
 	default:
 		return OutputFormatJSON, fmt.Errorf("invalid output format '%s'", value)
 	}
 }
+
+// outputFormatValue is the value of the format flag. Besides the named formats, it accepts a Go template
+// which is validated when the flag is set.
+type outputFormatValue struct {
+	value string
+}
+
+func (v *outputFormatValue) String() string {
+	if v.value == "" {
+		return "text"
+	}
+
+	return v.value
+}
+
+func (v *outputFormatValue) Set(value string) error {
+	format, err := OutputFormatFromString(value)
+	if err != nil {
+		return err
+	}
+
+	if format == OutputFormatGoTemplate {
+		_, err = log.NewTemplateFormatter(strings.TrimPrefix(value, goTemplatePrefix))
+		if err != nil {
+			return err
+		}
+	}
+
+	v.value = value
+
+	return nil
+}
+
+func (v *outputFormatValue) Type() string {
+	return "format"
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/majd/ipatool/v2/pkg/progress"
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/cobra"
+)
+
+const (
+	progressFormatBar  = "bar"
+	progressFormatJSON = "json"
+	progressFormatNone = "none"
+)
+
+type progressFlags struct {
+	format string
+	fd     int
+}
+
+func (f *progressFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.format, "progress", progressFormatBar, "How to report the progress of downloads: 'bar' (interactive sessions only), 'json' (newline-delimited JSON events) or 'none'")
+	cmd.Flags().IntVar(&f.fd, "progress-fd", 2, "The file descriptor JSON progress events are written to (defaults to stderr)")
+}
+
+// jsonWriter returns the writer of JSON progress events, or nil when the format is not JSON.
+func (f *progressFlags) jsonWriter() (*progress.JSONWriter, error) {
+	switch f.format {
+	case progressFormatBar, progressFormatNone:
+		return nil, nil
+	case progressFormatJSON:
+	default:
+		return nil, fmt.Errorf("invalid progress format %q; supported formats are bar, json and none", f.format)
+	}
+
+	output := os.Stderr
+	if f.fd != 2 {
+		output = os.NewFile(uintptr(f.fd), fmt.Sprintf("fd%d", f.fd))
+		if output == nil {
+			return nil, fmt.Errorf("invalid progress file descriptor %d", f.fd)
+		}
+	}
+
+	return progress.NewJSONWriter(progress.JSONWriterArgs{Writer: output}), nil
+}
+
+// newDownloadProgress returns the progress of a download in the specified format, or nil when the
+// progress is not reported.
+func newDownloadProgress(cmd *cobra.Command, flags progressFlags) (progress.Progress, error) {
+	writer, err := flags.jsonWriter()
+	if err != nil {
+		return nil, err
+	}
+
+	if writer != nil {
+		return writer.Progress(""), nil
+	}
+
+	if flags.format == progressFormatNone {
+		return nil, nil
+	}
+
+	interactive, _ := cmd.Context().Value(interactiveKey).(bool)
+
+	return newProgressBar(interactive), nil
+}
+
+// newProgressBar returns a progress bar, or nil when the session is not interactive.
+func newProgressBar(interactive bool) progress.Progress {
+	if !interactive {
+		return nil
+	}
+
+	return progress.NewBar(progressbar.NewOptions64(1,
+		progressbar.OptionSetDescription("downloading"),
+		progressbar.OptionSetWriter(os.Stdout),
+		progressbar.OptionShowBytes(true),
+		progressbar.OptionSetWidth(20),
+		progressbar.OptionFullWidth(),
+		progressbar.OptionThrottle(65*time.Millisecond),
+		progressbar.OptionShowCount(),
+		progressbar.OptionClearOnFinish(),
+		progressbar.OptionSpinnerType(14),
+		progressbar.OptionSetRenderBlankState(true),
+		progressbar.OptionSetElapsedTime(false),
+		progressbar.OptionSetPredictTime(false),
+	))
+}
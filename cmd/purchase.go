@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/avast/retry-go"
@@ -18,62 +17,14 @@ func purchaseCmd() *cobra.Command {
 		Use:   "purchase",
 		Short: "Obtain a license for the app from the App Store",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var lastErr error
-			var acc appstore.Account
-
-			return retry.Do(func() error {
-				infoResult, err := dependencies.AppStore.AccountInfo()
-				if err != nil {
-					return err
-				}
-
-				acc = infoResult.Account
-
-				if errors.Is(lastErr, appstore.ErrPasswordTokenExpired) {
-					bagOutput, err := dependencies.AppStore.Bag(appstore.BagInput{})
-					if err != nil {
-						return fmt.Errorf("failed to get bag: %w", err)
-					}
-
-					loginResult, err := dependencies.AppStore.Login(appstore.LoginInput{
-						Email:    acc.Email,
-						Password: acc.Password,
-						Endpoint: bagOutput.AuthEndpoint,
-					})
-					if err != nil {
-						return err
-					}
-
-					acc = loginResult.Account
-				}
-
-				lookupResult, err := dependencies.AppStore.Lookup(appstore.LookupInput{Account: acc, BundleID: bundleID})
-				if err != nil {
-					return err
-				}
-
-				err = dependencies.AppStore.Purchase(appstore.PurchaseInput{Account: acc, App: lookupResult.App})
-				if err != nil && !errors.Is(err, appstore.ErrLicenseAlreadyExists) {
-					return err
-				}
-
-				dependencies.Logger.Log().
-					Bool("alreadyOwned", errors.Is(err, appstore.ErrLicenseAlreadyExists)).
-					Bool("success", true).
-					Send()
-
-				return nil
-			},
-				retry.LastErrorOnly(true),
-				retry.DelayType(retry.FixedDelay),
-				retry.Delay(time.Millisecond),
-				retry.Attempts(2),
-				retry.RetryIf(func(err error) bool {
-					lastErr = err
-
-					return errors.Is(err, appstore.ErrPasswordTokenExpired)
-				}),
-			)
+			alreadyOwned, err := purchaseApp(appstore.App{}, bundleID)
+			if err != nil {
+				return err
+			}
+
+			logResult(purchaseResult{AlreadyOwned: alreadyOwned})
+
+			return nil
 		},
 	}
 
@@ -82,3 +33,52 @@ func purchaseCmd() *cobra.Command {
 
 	return cmd
 }
+
+// purchaseApp obtains a license for the app, looking it up first when a bundle identifier is specified.
+// It reports whether the account already owned a license.
+// nolint:wrapcheck
+func purchaseApp(app appstore.App, bundleID string) (bool, error) {
+	var (
+		lastErr      error
+		alreadyOwned bool
+	)
+
+	err := retry.Do(func() error {
+		acc, err := resolveAccount(lastErr)
+		if err != nil {
+			return err
+		}
+
+		target := app
+
+		if bundleID != "" {
+			lookupResult, err := dependencies.AppStore.Lookup(appstore.LookupInput{Account: acc, BundleID: bundleID})
+			if err != nil {
+				return err
+			}
+
+			target = lookupResult.App
+		}
+
+		err = dependencies.AppStore.Purchase(appstore.PurchaseInput{Account: acc, App: target})
+		if err != nil && !errors.Is(err, appstore.ErrLicenseAlreadyExists) {
+			return err
+		}
+
+		alreadyOwned = errors.Is(err, appstore.ErrLicenseAlreadyExists)
+
+		return nil
+	},
+		retry.LastErrorOnly(true),
+		retry.DelayType(retry.FixedDelay),
+		retry.Delay(time.Millisecond),
+		retry.Attempts(2),
+		retry.RetryIf(func(err error) bool {
+			lastErr = err
+
+			return errors.Is(err, appstore.ErrPasswordTokenExpired)
+		}),
+	)
+
+	return alreadyOwned, err
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/majd/ipatool/v2/pkg/jobs"
+	"github.com/majd/ipatool/v2/pkg/library"
+	"github.com/majd/ipatool/v2/pkg/log"
+)
+
+// schemaVersion is the version of the output schema of the commands. The major version is incremented
+// on breaking changes, e.g. when a field is removed or renamed, and the minor version when fields are added.
+const schemaVersion = "1.1.0"
+
+// logResult writes the result of a command, along with the schema version and the success flag.
+func logResult(result interface{}) {
+	event := dependencies.Logger.Log().Str("schemaVersion", schemaVersion)
+	log.Fields(event, result).
+		Bool("success", true).
+		Send()
+}
+
+type errorResult struct {
+	Error           string `json:"error"`
+	Code            string `json:"code" description:"stable identifier of the class of the error"`
+	ExitCode        int    `json:"exitCode"`
+	FailureType     string `json:"failureType,omitempty" description:"raw failure type reported by the App Store"`
+	CustomerMessage string `json:"customerMessage,omitempty" description:"raw customer message reported by the App Store"`
+}
+
+type accountResult struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type authStatusResult struct {
+	Status              string `json:"status" description:"one of valid, expired or device-verification-failed"`
+	Name                string `json:"name"`
+	Email               string `json:"email"`
+	StoreFront          string `json:"storeFront"`
+	CountryCode         string `json:"countryCode"`
+	Pod                 string `json:"pod"`
+	DirectoryServicesID string `json:"directoryServicesID"`
+}
+
+type authRevokeResult struct{}
+
+type authExportResult struct {
+	Output string `json:"output"`
+	Email  string `json:"email"`
+}
+
+type authImportResult struct {
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	ExportedAt   time.Time `json:"exportedAt"`
+	TokenExpired bool      `json:"tokenExpired"`
+}
+
+type keychainMigrateResult struct {
+	Keys          []string `json:"keys"`
+	From          string   `json:"from"`
+	To            string   `json:"to"`
+	SourceRemoved bool     `json:"sourceRemoved"`
+}
+
+type downloadResult struct {
+	Output    string `json:"output" description:"path of the downloaded app package"`
+	Purchased bool   `json:"purchased"`
+	Hash      string `json:"hash,omitempty" description:"SHA-256 digest of the app package, when stored in the library"`
+	Skipped   bool   `json:"skipped,omitempty" description:"whether the version was already in the library, or the output file already existed, and was not downloaded"`
+}
+
+type libraryAddResult struct {
+	Count      int               `json:"count"`
+	Duplicates int               `json:"duplicates" description:"number of app packages which were already in the library"`
+	Packages   []library.Package `json:"packages"`
+}
+
+type libraryListResult struct {
+	Count    int               `json:"count"`
+	Packages []library.Package `json:"packages"`
+}
+
+type libraryGCResult struct {
+	DryRun         bool              `json:"dryRun"`
+	Count          int               `json:"count" description:"number of removed app packages"`
+	Kept           int               `json:"kept" description:"number of app packages kept by the policy"`
+	ReclaimedBytes int64             `json:"reclaimedBytes"`
+	Removed        []library.Package `json:"removed"`
+}
+
+type libraryPathResult struct {
+	Path string `json:"path"`
+}
+
+type jobsListResult struct {
+	Count int        `json:"count"`
+	Jobs  []jobs.Job `json:"jobs"`
+}
+
+type purchaseResult struct {
+	AlreadyOwned bool `json:"alreadyOwned"`
+}
+
+type searchResult struct {
+	Count   int                   `json:"count"`
+	Total   int                   `json:"total" description:"number of matching apps before pagination, at most 200"`
+	Offset  int64                 `json:"offset"`
+	HasMore bool                  `json:"hasMore"`
+	Apps    appstore.AppSelection `json:"apps"`
+}
+
+type lookupResult struct {
+	Count             int                   `json:"count"`
+	Apps              appstore.AppSelection `json:"apps"`
+	NotFoundAppIDs    []int64               `json:"notFoundAppIDs"`
+	NotFoundBundleIDs []string              `json:"notFoundBundleIDs"`
+}
+
+type storeFrontsResult struct {
+	Count       int                     `json:"count"`
+	StoreFronts appstore.StoreFrontList `json:"storeFronts"`
+}
+
+type availabilityResult struct {
+	BundleID       string                       `json:"bundleID"`
+	AvailableCount int                          `json:"availableCount"`
+	Countries      appstore.AvailabilityEntries `json:"countries"`
+}
+
+type developerResult struct {
+	ArtistID int64 `json:"artistID"`
+	Count    int   `json:"count"`
+	// Results precede the apps, so that the table, csv and ndjson formats render the outcomes when set.
+	Results developerAppResults   `json:"results,omitempty" description:"outcome of the purchase or download of each app"`
+	Apps    appstore.AppSelection `json:"apps"`
+}
+
+type listVersionsResult struct {
+	ExternalVersionIdentifiers []string `json:"externalVersionIdentifiers"`
+	BundleID                   string   `json:"bundleID"`
+}
+
+type getVersionMetadataResult struct {
+	ExternalVersionID string    `json:"externalVersionID"`
+	DisplayVersion    string    `json:"displayVersion"`
+	ReleaseDate       time.Time `json:"releaseDate"`
+}
+
+type configEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type configSetResult struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Path  string `json:"path" description:"path of the configuration file"`
+}
+
+type configUnsetResult struct {
+	Key  string `json:"key"`
+	Path string `json:"path" description:"path of the configuration file"`
+}
+
+type configListResult struct {
+	Path    string        `json:"path" description:"path of the configuration file"`
+	Count   int           `json:"count"`
+	Entries []configEntry `json:"entries"`
+}
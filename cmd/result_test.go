@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/majd/ipatool/v2/pkg/log"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("developerResult", func() {
+	format := func(formatter log.Formatter, result interface{}) string {
+		event := new(bytes.Buffer)
+		logger := zerolog.New(event)
+		log.Fields(logger.Info(), result).Send()
+
+		record, err := log.ParseRecord(event.Bytes())
+		Expect(err).ToNot(HaveOccurred())
+
+		out := new(bytes.Buffer)
+		Expect(formatter.Format(out, record.Without(zerolog.LevelFieldName))).To(Succeed())
+
+		return out.String()
+	}
+
+	When("the apps were downloaded", func() {
+		var result developerResult
+
+		BeforeEach(func() {
+			apps := []appstore.App{{ID: 1, BundleID: "com.example.one"}, {ID: 2, BundleID: "com.example.two"}}
+
+			result = developerResult{
+				ArtistID: 10,
+				Count:    len(apps),
+				Apps:     appstore.Apps(apps).WithFields(nil),
+				Results: developerAppResults{
+					{app: apps[0], status: "succeeded", output: "/apps/one.ipa"},
+					{app: apps[1], status: "failed", err: errors.New("download failed")},
+				},
+			}
+		})
+
+		It("renders the outcomes as csv rows", func() {
+			Expect(format(log.NewCSVFormatter(), result)).To(Equal("" +
+				"id,bundleID,status,output,error,errorCode\n" +
+				"1,com.example.one,succeeded,/apps/one.ipa,,\n" +
+				"2,com.example.two,failed,,download failed,unknown\n"))
+		})
+
+		It("renders the outcomes as ndjson lines", func() {
+			Expect(format(log.NewNDJSONFormatter(), result)).To(Equal("" +
+				`{"id":1,"bundleID":"com.example.one","status":"succeeded","output":"/apps/one.ipa"}` + "\n" +
+				`{"id":2,"bundleID":"com.example.two","status":"failed","error":"download failed","errorCode":"unknown"}` + "\n"))
+		})
+	})
+
+	When("the apps were only listed", func() {
+		It("renders the apps", func() {
+			result := developerResult{
+				ArtistID: 10,
+				Count:    1,
+				Apps:     appstore.Apps([]appstore.App{{ID: 1, BundleID: "com.example.one"}}).WithFields([]string{"id", "bundleID"}),
+			}
+
+			Expect(format(log.NewCSVFormatter(), result)).To(Equal("" +
+				"id,bundleID\n" +
+				"1,com.example.one\n"))
+		})
+	})
+})
@@ -6,8 +6,8 @@ import (
 	"reflect"
 
 	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/majd/ipatool/v2/pkg/log"
 	"github.com/spf13/cobra"
-	"github.com/thediveo/enumflag/v2"
 )
 
 var version = "dev"
@@ -20,7 +20,8 @@ func rootCmd() *cobra.Command {
 	var (
 		verbose        bool
 		nonInteractive bool
-		format         OutputFormat
+		format         outputFormatValue
+		configPath     string
 	)
 
 	cmd := &cobra.Command{
@@ -29,28 +30,66 @@ func rootCmd() *cobra.Command {
 		SilenceErrors: true,
 		SilenceUsage:  true,
 		Version:       version,
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := applyConfig(cmd)
+			if err != nil {
+				return err
+			}
+
 			ctx := context.WithValue(context.Background(), interactiveKey, !nonInteractive)
 			cmd.SetContext(ctx)
-			initWithCommand(cmd)
+
+			err = validateKeychainBackend(keychainBackend)
+			if err != nil {
+				return err
+			}
+
+			rateLimiter, err = newRateLimiter(limitRate, pauseDuring)
+			if err != nil {
+				return err
+			}
+
+			keychainEnviron = keychainEnvironment(cmd.Root())
+
+			err = initWithCommand(cmd)
+			if err != nil {
+				return err
+			}
+
+			dependencies.Config = cfg
+
+			return nil
 		},
 	}
 
-	cmd.PersistentFlags().VarP(
-		enumflag.New(&format, "format", map[OutputFormat][]string{
-			OutputFormatText: {"text"},
-			OutputFormatJSON: {"json"},
-		}, enumflag.EnumCaseSensitive), "format", "", "sets output format for command; can be 'text', 'json'")
+	cmd.PersistentFlags().Var(&format, "format", "sets output format for command; can be 'text', 'json', 'table', 'csv', 'yaml', 'ndjson' or 'go-template=<template>'")
+	cmd.PersistentFlags().StringVar(&configPath, configFlagName, "", "path of the configuration file (defaults to ~/.ipatool/config.yaml)")
 	cmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "enables verbose logs")
 	cmd.PersistentFlags().BoolVarP(&nonInteractive, "non-interactive", "", false, "run in non-interactive session")
 	cmd.PersistentFlags().StringVar(&keychainPassphrase, "keychain-passphrase", "", "passphrase for unlocking keychain")
+	cmd.PersistentFlags().StringVar(&keychainBackend, "keychain-backend", "", "keychain backend to use; can be 'keychain', 'secret-service', 'file', 'age', 'memory' (defaults to the first available of keychain, secret-service and file)")
+	cmd.PersistentFlags().StringVar(&keychainAgeIdentity, "keychain-age-identity", "", "path of the age identity file used to decrypt the 'age' keychain backend")
+	cmd.PersistentFlags().StringVar(&limitRate, "limit-rate", "", "maximum rate of the transfers of app packages in bytes per second, shared by concurrent downloads, e.g. '512K' or '20M'")
+	cmd.PersistentFlags().StringVar(&pauseDuring, "pause-during", "", "comma-separated time of day windows during which the transfers of app packages are paused, in local time, e.g. '09:00-18:00'")
+	cmd.PersistentFlags().StringArrayVar(&postDownloadHooks, "post-download-hook", nil, "shell command run after each download with a JSON description of the app package on stdin; a non-zero exit status fails the download (can be repeated)")
+	cmd.PersistentFlags().StringSliceVar(&keychainAgeRecipients, "keychain-age-recipient", nil, "age recipient the 'age' keychain backend is encrypted to (defaults to the identity's recipient)")
 
 	cmd.AddCommand(authCmd())
+	cmd.AddCommand(keychainCmd())
 	cmd.AddCommand(downloadCmd())
 	cmd.AddCommand(purchaseCmd())
 	cmd.AddCommand(searchCmd())
+	cmd.AddCommand(lookupCmd())
+	cmd.AddCommand(storeFrontsCmd())
+	cmd.AddCommand(availabilityCmd())
+	cmd.AddCommand(developerCmd())
 	cmd.AddCommand(ListVersionsCmd())
 	cmd.AddCommand(getVersionMetadataCmd())
+	cmd.AddCommand(serveCmd())
+	cmd.AddCommand(jobsCmd())
+	cmd.AddCommand(libraryCmd())
+	cmd.AddCommand(configCmd())
+	cmd.AddCommand(schemaCmd())
 
 	return cmd
 }
@@ -62,7 +101,8 @@ func Execute() int {
 
 	if err != nil {
 		if reflect.ValueOf(dependencies).IsZero() {
-			initWithCommand(cmd)
+			// Only the logger is needed to report the error.
+			_ = initWithCommand(cmd)
 		}
 
 		var appstoreErr *appstore.Error
@@ -75,13 +115,27 @@ func Execute() int {
 			dependencies.Logger.Verbose().Stack().Err(err).Send()
 		}
 
-		dependencies.Logger.Error().
-			Err(err).
+		code := appstore.ErrorCodeOf(err)
+		exitCode := exitCodeOf(code)
+
+		result := errorResult{
+			Error:    err.Error(),
+			Code:     string(code),
+			ExitCode: exitCode,
+		}
+
+		if appstoreErr != nil {
+			result.FailureType = appstoreErr.FailureType
+			result.CustomerMessage = appstoreErr.CustomerMessage
+		}
+
+		event := dependencies.Logger.Error().Str("schemaVersion", schemaVersion)
+		log.Fields(event, result).
 			Bool("success", false).
 			Send()
 
-		return 1
+		return exitCode
 	}
 
-	return 0
+	return exitCodeSuccess
 }
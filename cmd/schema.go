@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/majd/ipatool/v2/pkg/jobs"
+	"github.com/majd/ipatool/v2/pkg/library"
+	"github.com/majd/ipatool/v2/pkg/schema"
+	"github.com/spf13/cobra"
+)
+
+// errorSchemaName is the name under which the schema of failed commands is available.
+const errorSchemaName = "error"
+
+// resultTypes maps the name of each command to its result.
+var resultTypes = map[string]interface{}{
+	"auth login":           accountResult{},
+	"auth info":            accountResult{},
+	"auth status":          authStatusResult{},
+	"auth revoke":          authRevokeResult{},
+	"auth export":          authExportResult{},
+	"auth import":          authImportResult{},
+	"keychain migrate":     keychainMigrateResult{},
+	"download":             downloadResult{},
+	"purchase":             purchaseResult{},
+	"search":               searchResult{},
+	"lookup":               lookupResult{},
+	"storefronts":          storeFrontsResult{},
+	"availability":         availabilityResult{},
+	"developer":            developerResult{},
+	"list-versions":        listVersionsResult{},
+	"get-version-metadata": getVersionMetadataResult{},
+	"config get":           configEntry{},
+	"config set":           configSetResult{},
+	"config unset":         configUnsetResult{},
+	"config list":          configListResult{},
+	"jobs add":             jobs.Job{},
+	"jobs list":            jobsListResult{},
+	"jobs cancel":          jobs.Job{},
+	"jobs retry":           jobs.Job{},
+	"jobs run":             jobsListResult{},
+	"library add":          libraryAddResult{},
+	"library list":         libraryListResult{},
+	"library find":         libraryListResult{},
+	"library remove":       library.Package{},
+	"library path":         libraryPathResult{},
+	"library gc":           libraryGCResult{},
+	"schema":               schemaListResult{},
+	errorSchemaName:        errorResult{},
+}
+
+// nolint:wrapcheck
+func schemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema [<command>]",
+		Short: "Print the JSON Schema of the output of a command",
+		Long: "Print the JSON Schema of the output of a command, e.g. 'ipatool schema auth login', or of failed " +
+			"commands with 'ipatool schema error'. Lists the supported commands when no command is specified.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				logResult(schemaListResult{Commands: schemaNames()})
+
+				return nil
+			}
+
+			name := strings.Join(args, " ")
+
+			result, ok := resultTypes[name]
+			if !ok {
+				return fmt.Errorf("unknown command %q; supported commands are: %s", name, strings.Join(schemaNames(), ", "))
+			}
+
+			data, err := json.MarshalIndent(commandSchema(name, result), "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode schema: %w", err)
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+
+			return err
+		},
+	}
+}
+
+type schemaListResult struct {
+	Commands []string `json:"commands"`
+}
+
+// commandSchema returns the schema of the output of the command, including the fields added to every result.
+func commandSchema(name string, result interface{}) *schema.Schema {
+	document := schema.Generate(result)
+	document.Schema = schema.Draft
+	document.Title = fmt.Sprintf("ipatool %s output", name)
+	document.Properties["schemaVersion"] = &schema.Schema{Type: "string", Const: schemaVersion}
+	document.Properties["success"] = &schema.Schema{Type: "boolean", Const: name != errorSchemaName}
+	document.Required = append([]string{"schemaVersion"}, append(document.Required, "success")...)
+
+	return document
+}
+
+func schemaNames() []string {
+	names := make([]string, 0, len(resultTypes))
+	for name := range resultTypes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -10,6 +10,14 @@ func searchCmd() *cobra.Command {
 	var (
 		limit         int64
 		platformValue string
+		country       string
+		fields        []string
+		offset        int64
+		sortValue     string
+		descending    bool
+		filter        appstore.SearchFilter
+		minPrice      float64
+		maxPrice      float64
 	)
 
 	cmd := &cobra.Command{
@@ -17,30 +25,63 @@ func searchCmd() *cobra.Command {
 		Short: "Search for iOS and tvOS apps available on the App Store",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			infoResult, err := dependencies.AppStore.AccountInfo()
+			err := appstore.ValidateAppFields(fields)
 			if err != nil {
 				return err
 			}
 
+			order, err := appstore.ParseSearchSort(sortValue)
+			if err != nil {
+				return err
+			}
+
+			if cmd.Flags().Changed("min-price") {
+				filter.MinPrice = &minPrice
+			}
+
+			if cmd.Flags().Changed("max-price") {
+				filter.MaxPrice = &maxPrice
+			}
+
+			var acc appstore.Account
+
+			// The country is derived from the account's store front unless it is specified explicitly.
+			if country == "" {
+				infoResult, err := dependencies.AppStore.AccountInfo()
+				if err != nil {
+					return err
+				}
+
+				acc = infoResult.Account
+			}
+
 			platform, err := appstore.ParsePlatform(platformValue)
 			if err != nil {
 				return err
 			}
 
 			output, err := dependencies.AppStore.Search(appstore.SearchInput{
-				Account:  infoResult.Account,
-				Term:     args[0],
-				Limit:    limit,
-				Platform: platform,
+				Account:    acc,
+				Term:       args[0],
+				Limit:      limit,
+				Platform:   platform,
+				Country:    country,
+				Offset:     offset,
+				Filter:     filter,
+				Sort:       order,
+				Descending: descending,
 			})
 			if err != nil {
 				return err
 			}
 
-			dependencies.Logger.Log().
-				Int("count", output.Count).
-				Array("apps", appstore.Apps(output.Results)).
-				Send()
+			logResult(searchResult{
+				Count:   output.Count,
+				Total:   output.Total,
+				Offset:  offset,
+				HasMore: output.HasMore,
+				Apps:    appstore.Apps(output.Results).WithFields(fields),
+			})
 
 			return nil
 		},
@@ -48,6 +89,17 @@ func searchCmd() *cobra.Command {
 
 	cmd.Flags().Int64VarP(&limit, "limit", "l", 5, "maximum amount of search results to retrieve")
 	cmd.Flags().StringVar(&platformValue, "platform", "", "Platform to search: iphone, ipad, or appletv")
+	cmd.Flags().StringVar(&country, "country", "", "Two-letter code of the country to search in (defaults to the account's country)")
+	cmd.Flags().Int64Var(&offset, "offset", 0, "number of matching search results to skip")
+	cmd.Flags().BoolVar(&filter.FreeOnly, "free", false, "only include free apps")
+	cmd.Flags().Float64Var(&minPrice, "min-price", 0, "only include apps costing at least the specified price")
+	cmd.Flags().Float64Var(&maxPrice, "max-price", 0, "only include apps costing at most the specified price")
+	cmd.Flags().Float64Var(&filter.MinRating, "min-rating", 0, "only include apps with at least the specified average user rating")
+	cmd.Flags().StringVar(&filter.Genre, "genre", "", "only include apps in the specified genre")
+	cmd.Flags().StringVar(&filter.Seller, "seller", "", "only include apps whose seller or artist name contains the specified text")
+	cmd.Flags().StringVar(&sortValue, "sort", "", "sort order of the search results: relevance, name, price, rating, rating-count, or release-date")
+	cmd.Flags().BoolVar(&descending, "desc", false, "sort the search results in descending order")
+	cmd.Flags().StringSliceVar(&fields, "fields", nil, "App fields to include in the output (defaults to all fields)")
 
 	return cmd
 }
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/majd/ipatool/v2/pkg/jobs"
+	"github.com/majd/ipatool/v2/pkg/server"
+	"github.com/spf13/cobra"
+)
+
+// nolint:wrapcheck
+func serveCmd() *cobra.Command {
+	var (
+		address     string
+		token       string
+		outputPath  string
+		concurrency int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the App Store operations as a JSON REST API",
+		Long: "Serve the App Store operations as a JSON REST API. Requests must carry the token in an " +
+			"'Authorization: Bearer <token>' header. The OpenAPI document of the API is served at /openapi.json. " +
+			"Download jobs are added to the persistent queue managed by the 'jobs' commands and run by the server.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if token == "" {
+				return errors.New("a token is required; use the \"--token\" flag or the IPATOOL_SERVE_TOKEN environment variable")
+			}
+
+			if concurrency < 1 {
+				return errors.New("concurrency must be at least 1")
+			}
+
+			if outputPath != "" {
+				info, err := dependencies.OS.Stat(outputPath)
+				if err != nil || !info.IsDir() {
+					return fmt.Errorf("output path %q must be an existing directory", outputPath)
+				}
+			}
+
+			// The jobs may be run by another process from another directory.
+			absOutputPath, err := filepath.Abs(outputPath)
+			if err != nil {
+				return fmt.Errorf("failed to resolve output path: %w", err)
+			}
+
+			listener, err := net.Listen("tcp", address)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", address, err)
+			}
+
+			queue := newJobQueue(concurrency)
+
+			srv := server.New(server.Args{
+				AppStore:   dependencies.AppStore,
+				Jobs:       queue,
+				OutputPath: absOutputPath,
+				Logger:     dependencies.Logger,
+				Token:      token,
+				Version:    version,
+			})
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			queueErrs := make(chan error, 1)
+
+			go func() {
+				err := queue.Run(ctx)
+				if errors.Is(err, jobs.ErrRunnerActive) {
+					dependencies.Logger.Verbose().Msg("download jobs are run by another process")
+
+					err = nil
+				}
+
+				queueErrs <- err
+			}()
+
+			dependencies.Logger.Log().
+				Str("address", listener.Addr().String()).
+				Msg("listening for requests")
+
+			err = srv.Serve(ctx, listener)
+			stop()
+
+			return errors.Join(err, <-queueErrs)
+		},
+	}
+
+	cmd.Flags().StringVar(&address, "listen", "127.0.0.1:8080", "The address to listen on")
+	cmd.Flags().StringVar(&token, "token", "", "The bearer token clients must authenticate with (required)")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "The directory to download app packages to (defaults to the current directory)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 1, "The maximum number of download jobs running at once")
+
+	return cmd
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/spf13/cobra"
+)
+
+func storeFrontsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "storefronts",
+		Short: "List the supported country codes and their store front identifiers",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			storeFronts := appstore.StoreFronts()
+
+			logResult(storeFrontsResult{
+				Count:       len(storeFronts),
+				StoreFronts: storeFronts,
+			})
+
+			return nil
+		},
+	}
+}
@@ -1,5 +1,10 @@
 package appstore
 
+import (
+	"fmt"
+	"strings"
+)
+
 type Account struct {
 	Email               string `json:"email,omitempty"`
 	PasswordToken       string `json:"passwordToken,omitempty"`
@@ -8,4 +13,21 @@ type Account struct {
 	StoreFront          string `json:"storeFront,omitempty"`
 	Password            string `json:"password,omitempty"`
 	Pod                 string `json:"pod,omitempty"`
+	GUID                string `json:"guid,omitempty"`
+}
+
+// deviceGUID returns the device identifier the account's session is bound to. Accounts imported
+// from another machine carry the GUID they were authenticated with; otherwise it is derived from
+// the local MAC address.
+func (t *appstore) deviceGUID(acc Account) (string, error) {
+	if acc.GUID != "" {
+		return acc.GUID, nil
+	}
+
+	macAddr, err := t.machine.MacAddress()
+	if err != nil {
+		return "", fmt.Errorf("failed to get mac address: %w", err)
+	}
+
+	return strings.ReplaceAll(strings.ToUpper(macAddr), ":", ""), nil
 }
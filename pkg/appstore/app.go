@@ -1,15 +1,38 @@
 package appstore
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/majd/ipatool/v2/pkg/schema"
 	"github.com/rs/zerolog"
 )
 
 type App struct {
-	ID       int64   `json:"trackId,omitempty"`
-	BundleID string  `json:"bundleId,omitempty"`
-	Name     string  `json:"trackName,omitempty"`
-	Version  string  `json:"version,omitempty"`
-	Price    float64 `json:"price,omitempty"`
+	ID                        int64     `json:"trackId,omitempty"`
+	BundleID                  string    `json:"bundleId,omitempty"`
+	Name                      string    `json:"trackName,omitempty"`
+	Version                   string    `json:"version,omitempty"`
+	Price                     float64   `json:"price,omitempty"`
+	Currency                  string    `json:"currency,omitempty"`
+	ArtistID                  int64     `json:"artistId,omitempty"`
+	ArtistName                string    `json:"artistName,omitempty"`
+	SellerName                string    `json:"sellerName,omitempty"`
+	Genres                    []string  `json:"genres,omitempty"`
+	PrimaryGenre              string    `json:"primaryGenreName,omitempty"`
+	MinimumOSVersion          string    `json:"minimumOsVersion,omitempty"`
+	FileSizeBytes             int64     `json:"fileSizeBytes,omitempty,string"`
+	SupportedDevices          []string  `json:"supportedDevices,omitempty"`
+	ContentRating             string    `json:"contentAdvisoryRating,omitempty"`
+	ReleaseDate               time.Time `json:"releaseDate,omitempty"`
+	CurrentVersionReleaseDate time.Time `json:"currentVersionReleaseDate,omitempty"`
+	ArtworkURL60              string    `json:"artworkUrl60,omitempty"`
+	ArtworkURL100             string    `json:"artworkUrl100,omitempty"`
+	ArtworkURL512             string    `json:"artworkUrl512,omitempty"`
+	Description               string    `json:"description,omitempty"`
+	AverageUserRating         float64   `json:"averageUserRating,omitempty"`
+	UserRatingCount           int64     `json:"userRatingCount,omitempty"`
 }
 
 type VersionHistoryInfo struct {
@@ -27,6 +50,157 @@ type VersionDetails struct {
 
 type Apps []App
 
+// appField describes how a single app attribute is rendered in the output. Optional fields are
+// omitted when empty unless they are selected explicitly.
+type appField struct {
+	name     string
+	optional bool
+	schema   *schema.Schema
+	empty    func(App) bool
+	write    func(*zerolog.Event, App)
+}
+
+var appFields = []appField{
+	{name: "id", schema: schema.Of("integer"), write: func(e *zerolog.Event, a App) { e.Int64("id", a.ID) }},
+	{name: "bundleID", schema: schema.Of("string"), write: func(e *zerolog.Event, a App) { e.Str("bundleID", a.BundleID) }},
+	{name: "name", schema: schema.Of("string"), write: func(e *zerolog.Event, a App) { e.Str("name", a.Name) }},
+	{name: "version", schema: schema.Of("string"), write: func(e *zerolog.Event, a App) { e.Str("version", a.Version) }},
+	{name: "price", schema: schema.Of("number"), write: func(e *zerolog.Event, a App) { e.Float64("price", a.Price) }},
+	{name: "currency", schema: schema.Of("string"), write: func(e *zerolog.Event, a App) { e.Str("currency", a.Currency) }},
+	{
+		name: "artistID", optional: true, schema: schema.Of("integer"),
+		empty: func(a App) bool { return a.ArtistID == 0 },
+		write: func(e *zerolog.Event, a App) { e.Int64("artistID", a.ArtistID) },
+	},
+	{
+		name: "artistName", optional: true, schema: schema.Of("string"),
+		empty: func(a App) bool { return a.ArtistName == "" },
+		write: func(e *zerolog.Event, a App) { e.Str("artistName", a.ArtistName) },
+	},
+	{
+		name: "sellerName", optional: true, schema: schema.Of("string"),
+		empty: func(a App) bool { return a.SellerName == "" },
+		write: func(e *zerolog.Event, a App) { e.Str("sellerName", a.SellerName) },
+	},
+	{
+		name: "genres", optional: true, schema: schema.ArrayOf(schema.Of("string")),
+		empty: func(a App) bool { return len(a.Genres) == 0 },
+		write: func(e *zerolog.Event, a App) { e.Strs("genres", a.Genres) },
+	},
+	{
+		name: "primaryGenre", optional: true, schema: schema.Of("string"),
+		empty: func(a App) bool { return a.PrimaryGenre == "" },
+		write: func(e *zerolog.Event, a App) { e.Str("primaryGenre", a.PrimaryGenre) },
+	},
+	{
+		name: "minimumOSVersion", optional: true, schema: schema.Of("string"),
+		empty: func(a App) bool { return a.MinimumOSVersion == "" },
+		write: func(e *zerolog.Event, a App) { e.Str("minimumOSVersion", a.MinimumOSVersion) },
+	},
+	{
+		name: "fileSizeBytes", optional: true, schema: schema.Of("integer"),
+		empty: func(a App) bool { return a.FileSizeBytes == 0 },
+		write: func(e *zerolog.Event, a App) { e.Int64("fileSizeBytes", a.FileSizeBytes) },
+	},
+	{
+		name: "supportedDevices", optional: true, schema: schema.ArrayOf(schema.Of("string")),
+		empty: func(a App) bool { return len(a.SupportedDevices) == 0 },
+		write: func(e *zerolog.Event, a App) { e.Strs("supportedDevices", a.SupportedDevices) },
+	},
+	{
+		name: "contentRating", optional: true, schema: schema.Of("string"),
+		empty: func(a App) bool { return a.ContentRating == "" },
+		write: func(e *zerolog.Event, a App) { e.Str("contentRating", a.ContentRating) },
+	},
+	{
+		name: "releaseDate", optional: true, schema: schema.DateTime(),
+		empty: func(a App) bool { return a.ReleaseDate.IsZero() },
+		write: func(e *zerolog.Event, a App) { e.Time("releaseDate", a.ReleaseDate) },
+	},
+	{
+		name: "currentVersionReleaseDate", optional: true, schema: schema.DateTime(),
+		empty: func(a App) bool { return a.CurrentVersionReleaseDate.IsZero() },
+		write: func(e *zerolog.Event, a App) { e.Time("currentVersionReleaseDate", a.CurrentVersionReleaseDate) },
+	},
+	{
+		name: "artworkURL60", optional: true, schema: schema.Of("string"),
+		empty: func(a App) bool { return a.ArtworkURL60 == "" },
+		write: func(e *zerolog.Event, a App) { e.Str("artworkURL60", a.ArtworkURL60) },
+	},
+	{
+		name: "artworkURL100", optional: true, schema: schema.Of("string"),
+		empty: func(a App) bool { return a.ArtworkURL100 == "" },
+		write: func(e *zerolog.Event, a App) { e.Str("artworkURL100", a.ArtworkURL100) },
+	},
+	{
+		name: "artworkURL512", optional: true, schema: schema.Of("string"),
+		empty: func(a App) bool { return a.ArtworkURL512 == "" },
+		write: func(e *zerolog.Event, a App) { e.Str("artworkURL512", a.ArtworkURL512) },
+	},
+	{
+		name: "description", optional: true, schema: schema.Of("string"),
+		empty: func(a App) bool { return a.Description == "" },
+		write: func(e *zerolog.Event, a App) { e.Str("description", a.Description) },
+	},
+	{
+		name: "averageUserRating", optional: true, schema: schema.Of("number"),
+		empty: func(a App) bool { return a.AverageUserRating == 0 },
+		write: func(e *zerolog.Event, a App) { e.Float64("averageUserRating", a.AverageUserRating) },
+	},
+	{
+		name: "userRatingCount", optional: true, schema: schema.Of("integer"),
+		empty: func(a App) bool { return a.UserRatingCount == 0 },
+		write: func(e *zerolog.Event, a App) { e.Int64("userRatingCount", a.UserRatingCount) },
+	},
+}
+
+// AppFieldNames returns the names of the app fields that can be selected for output.
+func AppFieldNames() []string {
+	names := make([]string, len(appFields))
+	for i, field := range appFields {
+		names[i] = field.name
+	}
+
+	return names
+}
+
+// ValidateAppFields returns an error if any of the specified fields is unknown.
+func ValidateAppFields(fields []string) error {
+	for _, name := range fields {
+		found := false
+
+		for _, field := range appFields {
+			if field.name == name {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("unknown app field %q; supported fields are: %s", name, strings.Join(AppFieldNames(), ", "))
+		}
+	}
+
+	return nil
+}
+
+// WithFields returns a marshaler that only renders the specified fields of each app. All fields are
+// rendered when no field is specified.
+func (apps Apps) WithFields(fields []string) AppSelection {
+	return AppSelection{apps: apps, fields: fields}
+}
+
+// JSONSchema describes the rendered apps. Every field is optional since fields can be deselected.
+func (apps Apps) JSONSchema() *schema.Schema {
+	properties := map[string]*schema.Schema{}
+	for _, field := range appFields {
+		properties[field.name] = field.schema
+	}
+
+	return schema.ArrayOf(schema.Object(properties))
+}
+
 func (apps Apps) MarshalZerologArray(a *zerolog.Array) {
 	for _, app := range apps {
 		a.Object(app)
@@ -34,10 +208,48 @@ func (apps Apps) MarshalZerologArray(a *zerolog.Array) {
 }
 
 func (a App) MarshalZerologObject(event *zerolog.Event) {
-	event.
-		Int64("id", a.ID).
-		Str("bundleID", a.BundleID).
-		Str("name", a.Name).
-		Str("version", a.Version).
-		Float64("price", a.Price)
+	for _, field := range appFields {
+		if field.optional && field.empty(a) {
+			continue
+		}
+
+		field.write(event, a)
+	}
+}
+
+// AppSelection renders the selected fields of apps.
+type AppSelection struct {
+	apps   Apps
+	fields []string
+}
+
+func (s AppSelection) JSONSchema() *schema.Schema {
+	return s.apps.JSONSchema()
+}
+
+type selectedApp struct {
+	app    App
+	fields []string
+}
+
+func (s AppSelection) MarshalZerologArray(a *zerolog.Array) {
+	for _, app := range s.apps {
+		if len(s.fields) == 0 {
+			a.Object(app)
+
+			continue
+		}
+
+		a.Object(selectedApp{app: app, fields: s.fields})
+	}
+}
+
+func (s selectedApp) MarshalZerologObject(event *zerolog.Event) {
+	for _, name := range s.fields {
+		for _, field := range appFields {
+			if field.name == name {
+				field.write(event, s.app)
+			}
+		}
+	}
 }
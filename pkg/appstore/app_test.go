@@ -3,6 +3,7 @@ package appstore
 import (
 	"bytes"
 	"encoding/json"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -46,6 +47,7 @@ var _ = Describe("App", func() {
 			Name:     "app name",
 			Version:  "1.0",
 			Price:    0,
+			Currency: "USD",
 		}
 
 		buffer := bytes.NewBuffer([]byte{})
@@ -63,6 +65,88 @@ var _ = Describe("App", func() {
 		Expect(out["name"]).To(Equal("app name"))
 		Expect(out["version"]).To(Equal("1.0"))
 		Expect(out["price"]).To(Equal(float64(0)))
+		Expect(out["currency"]).To(Equal("USD"))
+	})
+
+	It("unmarshals iTunes lookup result", func() {
+		var app App
+
+		err := json.Unmarshal([]byte(`{
+			"trackId": 42,
+			"bundleId": "app.bundle.id",
+			"artistId": 7,
+			"artistName": "Artist",
+			"sellerName": "Seller Inc.",
+			"genres": ["Games", "Puzzle"],
+			"primaryGenreName": "Games",
+			"minimumOsVersion": "15.0",
+			"fileSizeBytes": "123456",
+			"supportedDevices": ["iPhone15-iPhone15"],
+			"contentAdvisoryRating": "4+",
+			"releaseDate": "2020-01-02T03:04:05Z",
+			"currentVersionReleaseDate": "2024-01-02T03:04:05Z",
+			"artworkUrl512": "https://example.com/512.png",
+			"description": "Description",
+			"averageUserRating": 4.5,
+			"userRatingCount": 100
+		}`), &app)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(app.ArtistID).To(Equal(int64(7)))
+		Expect(app.SellerName).To(Equal("Seller Inc."))
+		Expect(app.Genres).To(Equal([]string{"Games", "Puzzle"}))
+		Expect(app.FileSizeBytes).To(Equal(int64(123456)))
+		Expect(app.ContentRating).To(Equal("4+"))
+		Expect(app.ReleaseDate).To(Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)))
+		Expect(app.AverageUserRating).To(Equal(4.5))
+	})
+
+	It("omits empty optional fields", func() {
+		buffer := bytes.NewBuffer([]byte{})
+		logger := zerolog.New(buffer)
+		logger.Log().Object("app", App{ID: 42, SellerName: "Seller"}).Send()
+
+		var out map[string]map[string]interface{}
+		err := json.Unmarshal(buffer.Bytes(), &out)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(out["app"]).To(HaveKey("price"))
+		Expect(out["app"]).To(HaveKeyWithValue("sellerName", "Seller"))
+		Expect(out["app"]).ToNot(HaveKey("description"))
+	})
+
+	It("marshals selected fields only", func() {
+		apps := Apps{{ID: 42, BundleID: "app.bundle.id", Name: "app name"}}
+
+		buffer := bytes.NewBuffer([]byte{})
+		logger := zerolog.New(buffer)
+		logger.Log().Array("apps", apps.WithFields([]string{"bundleID", "description"})).Send()
+
+		var out map[string][]map[string]interface{}
+		err := json.Unmarshal(buffer.Bytes(), &out)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(out["apps"]).To(HaveLen(1))
+		Expect(out["apps"][0]).To(Equal(map[string]interface{}{
+			"bundleID":    "app.bundle.id",
+			"description": "",
+		}))
+	})
+
+	It("validates field names", func() {
+		Expect(ValidateAppFields([]string{"id", "sellerName"})).To(Succeed())
+		Expect(ValidateAppFields([]string{"unknown"})).ToNot(Succeed())
+	})
+
+	It("describes every field in the schema", func() {
+		result := Apps{}.WithFields(nil).JSONSchema()
+
+		Expect(result.Type).To(Equal("array"))
+		Expect(result.Items.Properties).To(HaveLen(len(AppFieldNames())))
+		Expect(result.Items.Properties["id"].Type).To(Equal("integer"))
+		Expect(result.Items.Properties["genres"].Items.Type).To(Equal("string"))
+		Expect(result.Items.Properties["releaseDate"].Format).To(Equal("date-time"))
+		Expect(result.Items.Required).To(BeEmpty())
 	})
 
 	It("formats ipa name correctly", func() {
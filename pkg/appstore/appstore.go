@@ -5,17 +5,27 @@ import (
 	"github.com/majd/ipatool/v2/pkg/keychain"
 	"github.com/majd/ipatool/v2/pkg/util/machine"
 	"github.com/majd/ipatool/v2/pkg/util/operatingsystem"
+	"github.com/majd/ipatool/v2/pkg/util/ratelimit"
 )
 
+//go:generate go run go.uber.org/mock/mockgen -source=appstore.go -destination=appstore_mock.go -package appstore
 type AppStore interface {
 	// Login authenticates with the App Store.
 	Login(input LoginInput) (LoginOutput, error)
 	// AccountInfo returns the information of the authenticated account.
 	AccountInfo() (AccountInfoOutput, error)
+	// AccountStatus validates the credentials of the specified account against the App Store.
+	AccountStatus(input AccountStatusInput) (AccountStatusOutput, error)
 	// Revoke revokes the active credentials.
 	Revoke() error
 	// Lookup looks apps up based on the specified bundle identifier.
 	Lookup(input LookupInput) (LookupOutput, error)
+	// LookupBatch looks several apps up based on their app IDs and/or bundle identifiers.
+	LookupBatch(input LookupBatchInput) (LookupBatchOutput, error)
+	// Availability looks the specified app up across store fronts.
+	Availability(input AvailabilityInput) (AvailabilityOutput, error)
+	// DeveloperApps lists the apps published by the specified developer.
+	DeveloperApps(input DeveloperAppsInput) (DeveloperAppsOutput, error)
 	// Search searches the App Store for apps matching the specified term.
 	Search(input SearchInput) (SearchOutput, error)
 	// Purchase acquires a license for the desired app.
@@ -44,6 +54,7 @@ type appstore struct {
 	httpClient     http.Client[interface{}]
 	machine        machine.Machine
 	os             operatingsystem.OperatingSystem
+	limiter        ratelimit.Limiter
 }
 
 type Args struct {
@@ -51,6 +62,8 @@ type Args struct {
 	CookieJar       http.CookieJar
 	OperatingSystem operatingsystem.OperatingSystem
 	Machine         machine.Machine
+	// Limiter limits the rate of the transfers of app packages, if set.
+	Limiter ratelimit.Limiter
 }
 
 func NewAppStore(args Args) AppStore {
@@ -69,5 +82,6 @@ func NewAppStore(args Args) AppStore {
 		httpClient:     http.NewClient[interface{}](clientArgs),
 		machine:        args.Machine,
 		os:             args.OperatingSystem,
+		limiter:        args.Limiter,
 	}
 }
@@ -2,15 +2,25 @@ package appstore
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+
+	"github.com/majd/ipatool/v2/pkg/keychain"
 )
 
+// ErrNotAuthenticated is returned when no account is stored in the keychain.
+var ErrNotAuthenticated = errors.New("not authenticated; use the \"auth login\" command to log in")
+
 type AccountInfoOutput struct {
 	Account Account
 }
 
 func (t *appstore) AccountInfo() (AccountInfoOutput, error) {
 	data, err := t.keychain.Get("account")
+	if errors.Is(err, keychain.ErrItemNotFound) {
+		return AccountInfoOutput{}, ErrNotAuthenticated
+	}
+
 	if err != nil {
 		return AccountInfoOutput{}, fmt.Errorf("failed to get account: %w", err)
 	}
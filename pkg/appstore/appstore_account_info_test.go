@@ -62,6 +62,20 @@ var _ = Describe("AppStore (AccountInfo)", func() {
 		})
 	})
 
+	When("keychain does not hold an account", func() {
+		BeforeEach(func() {
+			mockKeychain.EXPECT().
+				Get("account").
+				Return(nil, fmt.Errorf("%w: account", keychain.ErrItemNotFound))
+		})
+
+		It("returns not authenticated error", func() {
+			_, err := appstore.AccountInfo()
+			Expect(err).To(MatchError(ErrNotAuthenticated))
+			Expect(ErrorCodeOf(err)).To(Equal(ErrorCodeNotAuthenticated))
+		})
+	})
+
 	When("keychain returns invalid data", func() {
 		BeforeEach(func() {
 			mockKeychain.EXPECT().
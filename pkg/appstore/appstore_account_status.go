@@ -0,0 +1,79 @@
+package appstore
+
+import (
+	"fmt"
+)
+
+// probeAppID identifies the free app (TestFlight) used to validate credentials when no other app is specified.
+const probeAppID int64 = 899247664
+
+type AccountStatus string
+
+const (
+	AccountStatusValid                    AccountStatus = "valid"
+	AccountStatusExpired                  AccountStatus = "expired"
+	AccountStatusDeviceVerificationFailed AccountStatus = "device-verification-failed"
+)
+
+type AccountStatusInput struct {
+	Account Account
+	// AppID is the app used to probe the credentials. Defaults to a known free app.
+	AppID int64
+}
+
+type AccountStatusOutput struct {
+	Status      AccountStatus
+	CountryCode string
+}
+
+// AccountStatus validates the stored password token by performing a download request for a free app
+// without fetching the package itself.
+func (t *appstore) AccountStatus(input AccountStatusInput) (AccountStatusOutput, error) {
+	guid, err := t.deviceGUID(input.Account)
+	if err != nil {
+		return AccountStatusOutput{}, err
+	}
+
+	appID := input.AppID
+	if appID == 0 {
+		appID = probeAppID
+	}
+
+	countryCode, _ := countryCodeFromStoreFront(input.Account.StoreFront)
+
+	req := t.downloadRequest(input.Account, App{ID: appID}, guid, "")
+
+	res, err := t.downloadClient.Send(req)
+	if err != nil {
+		return AccountStatusOutput{}, fmt.Errorf("failed to send http request: %w", err)
+	}
+
+	var status AccountStatus
+
+	switch res.Data.FailureType {
+	case "", FailureTypeLicenseNotFound:
+		// The App Store only checks for a license once the credentials have been accepted.
+		status = AccountStatusValid
+	case FailureTypePasswordTokenExpired, FailureTypeSignInRequired:
+		status = AccountStatusExpired
+	case FailureTypeDeviceVerificationFailed:
+		status = AccountStatusDeviceVerificationFailed
+	default:
+		if res.Data.CustomerMessage == CustomerMessagePasswordChanged {
+			status = AccountStatusExpired
+
+			break
+		}
+
+		if res.Data.CustomerMessage != "" {
+			return AccountStatusOutput{}, newResponseError(fmt.Errorf("received error: %s", res.Data.CustomerMessage), res)
+		}
+
+		return AccountStatusOutput{}, newResponseError(fmt.Errorf("received error: %s", res.Data.FailureType), res)
+	}
+
+	return AccountStatusOutput{
+		Status:      status,
+		CountryCode: countryCode,
+	}, nil
+}
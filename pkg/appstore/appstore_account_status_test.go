@@ -0,0 +1,142 @@
+package appstore
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/majd/ipatool/v2/pkg/http"
+	"github.com/majd/ipatool/v2/pkg/util/machine"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("AppStore (AccountStatus)", func() {
+	var (
+		ctrl               *gomock.Controller
+		mockDownloadClient *http.MockClient[downloadResult]
+		mockMachine        *machine.MockMachine
+		as                 AppStore
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockDownloadClient = http.NewMockClient[downloadResult](ctrl)
+		mockMachine = machine.NewMockMachine(ctrl)
+		as = &appstore{
+			downloadClient: mockDownloadClient,
+			machine:        mockMachine,
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	When("fails to get MAC address", func() {
+		BeforeEach(func() {
+			mockMachine.EXPECT().
+				MacAddress().
+				Return("", errors.New(""))
+		})
+
+		It("returns error", func() {
+			_, err := as.AccountStatus(AccountStatusInput{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("request fails", func() {
+		BeforeEach(func() {
+			mockMachine.EXPECT().
+				MacAddress().
+				Return("00:00:00:00:00:00", nil)
+
+			mockDownloadClient.EXPECT().
+				Send(gomock.Any()).
+				Return(http.Result[downloadResult]{}, errors.New(""))
+		})
+
+		It("returns error", func() {
+			_, err := as.AccountStatus(AccountStatusInput{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("probe app is not specified", func() {
+		BeforeEach(func() {
+			mockMachine.EXPECT().
+				MacAddress().
+				Return("00:00:00:00:00:00", nil)
+
+			mockDownloadClient.EXPECT().
+				Send(gomock.Any()).
+				Do(func(req http.Request) {
+					Expect(strings.Contains(req.URL, PrivateAppStoreAPIPathDownload)).To(BeTrue())
+
+					payload, ok := req.Payload.(*http.XMLPayload)
+					Expect(ok).To(BeTrue())
+					Expect(payload.Content["salableAdamId"]).To(Equal(probeAppID))
+				}).
+				Return(http.Result[downloadResult]{}, nil)
+		})
+
+		It("probes the default app", func() {
+			_, err := as.AccountStatus(AccountStatusInput{})
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	DescribeTable("maps the failure type to a status",
+		func(failureType, customerMessage string, expected AccountStatus) {
+			mockMachine.EXPECT().
+				MacAddress().
+				Return("00:00:00:00:00:00", nil)
+
+			mockDownloadClient.EXPECT().
+				Send(gomock.Any()).
+				Return(http.Result[downloadResult]{
+					Data: downloadResult{
+						FailureType:     failureType,
+						CustomerMessage: customerMessage,
+					},
+				}, nil)
+
+			out, err := as.AccountStatus(AccountStatusInput{
+				Account: Account{StoreFront: "143441-1,29"},
+				AppID:   1,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out.Status).To(Equal(expected))
+			Expect(out.CountryCode).To(Equal("US"))
+		},
+		Entry("no failure", "", "", AccountStatusValid),
+		Entry("license not found", FailureTypeLicenseNotFound, "", AccountStatusValid),
+		Entry("password token expired", FailureTypePasswordTokenExpired, "", AccountStatusExpired),
+		Entry("sign in required", FailureTypeSignInRequired, "", AccountStatusExpired),
+		Entry("password changed", "1", CustomerMessagePasswordChanged, AccountStatusExpired),
+		Entry("device verification failed", FailureTypeDeviceVerificationFailed, "", AccountStatusDeviceVerificationFailed),
+	)
+
+	When("store returns an unknown failure", func() {
+		BeforeEach(func() {
+			mockMachine.EXPECT().
+				MacAddress().
+				Return("00:00:00:00:00:00", nil)
+
+			mockDownloadClient.EXPECT().
+				Send(gomock.Any()).
+				Return(http.Result[downloadResult]{
+					Data: downloadResult{
+						FailureType:     "999",
+						CustomerMessage: "something happened",
+					},
+				}, nil)
+		})
+
+		It("returns error", func() {
+			_, err := as.AccountStatus(AccountStatusInput{})
+			Expect(err).To(MatchError(ContainSubstring("something happened")))
+		})
+	})
+})
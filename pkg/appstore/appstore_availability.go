@@ -0,0 +1,175 @@
+package appstore
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/majd/ipatool/v2/pkg/schema"
+	"github.com/rs/zerolog"
+)
+
+const (
+	defaultAvailabilityConcurrency = 4
+)
+
+type AvailabilityInput struct {
+	BundleID string
+	Platform Platform
+	// Countries restricts the check to the specified country codes. Defaults to every supported store front.
+	Countries []string
+	// Concurrency is the maximum number of lookups in flight.
+	Concurrency int
+	// Interval is the minimum delay between two consecutive lookups. Zero disables rate limiting.
+	Interval time.Duration
+}
+
+type AvailabilityOutput struct {
+	Entries []AvailabilityEntry
+}
+
+type AvailabilityEntry struct {
+	CountryCode string
+	Available   bool
+	App         App
+	Error       error
+}
+
+type AvailabilityEntries []AvailabilityEntry
+
+func (e AvailabilityEntry) MarshalZerologObject(event *zerolog.Event) {
+	event.
+		Str("countryCode", e.CountryCode).
+		Bool("available", e.Available)
+
+	if e.Available {
+		event.
+			Int64("id", e.App.ID).
+			Str("version", e.App.Version).
+			Float64("price", e.App.Price).
+			Str("currency", e.App.Currency)
+	}
+
+	if e.Error != nil {
+		event.
+			Str("error", e.Error.Error()).
+			Str("errorCode", string(ErrorCodeOf(e.Error)))
+	}
+}
+
+func (entries AvailabilityEntries) JSONSchema() *schema.Schema {
+	return schema.ArrayOf(&schema.Schema{
+		Type: "object",
+		Properties: map[string]*schema.Schema{
+			"countryCode": schema.Of("string"),
+			"available":   schema.Of("boolean"),
+			"id":          schema.Of("integer"),
+			"version":     schema.Of("string"),
+			"price":       schema.Of("number"),
+			"currency":    schema.Of("string"),
+			"error":       schema.Of("string"),
+			"errorCode":   schema.Of("string"),
+		},
+		Required: []string{"countryCode", "available"},
+	})
+}
+
+func (entries AvailabilityEntries) MarshalZerologArray(a *zerolog.Array) {
+	for _, entry := range entries {
+		a.Object(entry)
+	}
+}
+
+// Availability looks the app up in every requested store front. Lookup failures are reported per country
+// instead of aborting the whole check.
+func (t *appstore) Availability(input AvailabilityInput) (AvailabilityOutput, error) {
+	countries := make([]string, 0, len(input.Countries))
+
+	for _, country := range input.Countries {
+		countryCode, err := ParseCountryCode(country)
+		if err != nil {
+			return AvailabilityOutput{}, err
+		}
+
+		countries = append(countries, countryCode)
+	}
+
+	if len(countries) == 0 {
+		for _, storeFront := range StoreFronts() {
+			countries = append(countries, storeFront.CountryCode)
+		}
+	}
+
+	concurrency := input.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultAvailabilityConcurrency
+	}
+
+	var throttle <-chan time.Time
+
+	if input.Interval > 0 {
+		ticker := time.NewTicker(input.Interval)
+		defer ticker.Stop()
+
+		throttle = ticker.C
+	}
+
+	var (
+		entries = make([]AvailabilityEntry, len(countries))
+		jobs    = make(chan int)
+		wg      sync.WaitGroup
+	)
+
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for index := range jobs {
+				entries[index] = t.availabilityEntry(input, countries[index])
+			}
+		}()
+	}
+
+	for index := range countries {
+		if throttle != nil && index > 0 {
+			<-throttle
+		}
+
+		jobs <- index
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CountryCode < entries[j].CountryCode
+	})
+
+	return AvailabilityOutput{
+		Entries: entries,
+	}, nil
+}
+
+func (t *appstore) availabilityEntry(input AvailabilityInput, countryCode string) AvailabilityEntry {
+	output, err := t.Lookup(LookupInput{
+		BundleID: input.BundleID,
+		Platform: input.Platform,
+		Country:  countryCode,
+	})
+	if errors.Is(err, ErrAppNotFound) {
+		return AvailabilityEntry{CountryCode: countryCode}
+	}
+
+	if err != nil {
+		return AvailabilityEntry{CountryCode: countryCode, Error: err}
+	}
+
+	return AvailabilityEntry{
+		CountryCode: countryCode,
+		Available:   true,
+		App:         output.App,
+	}
+}
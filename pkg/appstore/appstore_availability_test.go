@@ -0,0 +1,112 @@
+package appstore
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/majd/ipatool/v2/pkg/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("AppStore (Availability)", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockClient *http.MockClient[searchResult]
+		as         AppStore
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = http.NewMockClient[searchResult](ctrl)
+		as = &appstore{
+			searchClient: mockClient,
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	When("country is not supported", func() {
+		It("returns error", func() {
+			_, err := as.Availability(AvailabilityInput{
+				BundleID:  "app.bundle.id",
+				Countries: []string{"XX"},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("countries are specified", func() {
+		var testApp = App{
+			ID:       1,
+			BundleID: "app.bundle.id",
+			Version:  "1.0",
+			Price:    0.99,
+			Currency: "EUR",
+		}
+
+		BeforeEach(func() {
+			mockClient.EXPECT().
+				Send(gomock.Any()).
+				DoAndReturn(func(req http.Request) (http.Result[searchResult], error) {
+					parsedURL, err := url.Parse(req.URL)
+					Expect(err).ToNot(HaveOccurred())
+
+					switch parsedURL.Query().Get("country") {
+					case "DE":
+						return http.Result[searchResult]{
+							StatusCode: 200,
+							Data:       searchResult{Count: 1, Results: []App{testApp}},
+						}, nil
+					case "FR":
+						return http.Result[searchResult]{}, errors.New("request error")
+					default:
+						return http.Result[searchResult]{StatusCode: 200}, nil
+					}
+				}).
+				Times(3)
+		})
+
+		It("returns an entry per country", func() {
+			out, err := as.Availability(AvailabilityInput{
+				BundleID:    "app.bundle.id",
+				Countries:   []string{"us", "de", "fr"},
+				Concurrency: 2,
+				Interval:    time.Millisecond,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out.Entries).To(HaveLen(3))
+
+			Expect(out.Entries[0].CountryCode).To(Equal("DE"))
+			Expect(out.Entries[0].Available).To(BeTrue())
+			Expect(out.Entries[0].App).To(Equal(testApp))
+
+			Expect(out.Entries[1].CountryCode).To(Equal("FR"))
+			Expect(out.Entries[1].Available).To(BeFalse())
+			Expect(out.Entries[1].Error).To(HaveOccurred())
+
+			Expect(out.Entries[2].CountryCode).To(Equal("US"))
+			Expect(out.Entries[2].Available).To(BeFalse())
+			Expect(out.Entries[2].Error).ToNot(HaveOccurred())
+		})
+	})
+
+	When("countries are not specified", func() {
+		BeforeEach(func() {
+			mockClient.EXPECT().
+				Send(gomock.Any()).
+				Return(http.Result[searchResult]{StatusCode: 200}, nil).
+				Times(len(storeFronts))
+		})
+
+		It("checks every store front", func() {
+			out, err := as.Availability(AvailabilityInput{BundleID: "app.bundle.id"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out.Entries).To(HaveLen(len(storeFronts)))
+		})
+	})
+})
@@ -0,0 +1,128 @@
+package appstore
+
+import (
+	"errors"
+	"fmt"
+	gohttp "net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/majd/ipatool/v2/pkg/http"
+)
+
+const (
+	developerAppsLimit   = 200
+	developerSearchLimit = 50
+)
+
+var ErrDeveloperNotFound = errors.New("developer not found")
+
+type DeveloperAppsInput struct {
+	Account  Account
+	ArtistID int64
+	// SellerName is resolved to an artist ID via search when no artist ID is specified.
+	SellerName string
+	Platform   Platform
+	// Country overrides the country derived from the account's store front.
+	Country string
+}
+
+type DeveloperAppsOutput struct {
+	ArtistID int64
+	Apps     []App
+}
+
+// DeveloperApps lists the apps published by the specified developer.
+func (t *appstore) DeveloperApps(input DeveloperAppsInput) (DeveloperAppsOutput, error) {
+	countryCode, err := resolveCountryCode(input.Country, input.Account.StoreFront)
+	if err != nil {
+		return DeveloperAppsOutput{}, fmt.Errorf("failed to resolve the country code: %w", err)
+	}
+
+	artistID := input.ArtistID
+	if artistID == 0 {
+		if input.SellerName == "" {
+			return DeveloperAppsOutput{}, errors.New("either the artist ID or the seller name must be specified")
+		}
+
+		artistID, err = t.resolveArtistID(input.SellerName, countryCode, input.Platform)
+		if err != nil {
+			return DeveloperAppsOutput{}, fmt.Errorf("failed to resolve the artist ID: %w", err)
+		}
+	}
+
+	entity, err := input.Platform.developerEntity()
+	if err != nil {
+		return DeveloperAppsOutput{}, err
+	}
+
+	params := url.Values{}
+	params.Add("id", strconv.FormatInt(artistID, 10))
+	params.Add("entity", entity)
+	params.Add("limit", strconv.Itoa(developerAppsLimit))
+	params.Add("country", countryCode)
+
+	res, err := t.searchClient.Send(http.Request{
+		URL:            fmt.Sprintf("https://%s%s?%s", iTunesAPIDomain, iTunesAPIPathLookup, params.Encode()),
+		Method:         http.MethodGET,
+		ResponseFormat: http.ResponseFormatJSON,
+	})
+	if err != nil {
+		return DeveloperAppsOutput{}, fmt.Errorf("request failed: %w", err)
+	}
+
+	if res.StatusCode != gohttp.StatusOK {
+		return DeveloperAppsOutput{}, newResponseError(errors.New("invalid response"), res)
+	}
+
+	if len(res.Data.Results) == 0 {
+		return DeveloperAppsOutput{}, ErrDeveloperNotFound
+	}
+
+	// The first result describes the artist itself and carries no app ID.
+	apps := []App{}
+	seen := map[int64]bool{}
+
+	for _, app := range res.Data.Results {
+		if app.ID == 0 || seen[app.ID] {
+			continue
+		}
+
+		seen[app.ID] = true
+		apps = append(apps, app)
+	}
+
+	return DeveloperAppsOutput{
+		ArtistID: artistID,
+		Apps:     apps,
+	}, nil
+}
+
+func (t *appstore) resolveArtistID(sellerName, countryCode string, platform Platform) (int64, error) {
+	request, err := t.searchRequest(sellerName, countryCode, developerSearchLimit, platform)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create search request: %w", err)
+	}
+
+	res, err := t.searchClient.Send(request)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+
+	if res.StatusCode != gohttp.StatusOK {
+		return 0, newResponseError(errors.New("invalid response"), res)
+	}
+
+	for _, app := range res.Data.Results {
+		if app.ArtistID == 0 {
+			continue
+		}
+
+		if strings.EqualFold(app.SellerName, sellerName) || strings.EqualFold(app.ArtistName, sellerName) {
+			return app.ArtistID, nil
+		}
+	}
+
+	return 0, ErrDeveloperNotFound
+}
@@ -0,0 +1,212 @@
+package appstore
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/majd/ipatool/v2/pkg/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("AppStore (DeveloperApps)", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockClient *http.MockClient[searchResult]
+		as         AppStore
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = http.NewMockClient[searchResult](ctrl)
+		as = &appstore{
+			searchClient: mockClient,
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	When("neither artist ID nor seller name is specified", func() {
+		It("returns error", func() {
+			_, err := as.DeveloperApps(DeveloperAppsInput{
+				Account: Account{StoreFront: "143441"},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("store front is invalid", func() {
+		It("returns error", func() {
+			_, err := as.DeveloperApps(DeveloperAppsInput{
+				Account:  Account{StoreFront: "xyz"},
+				ArtistID: 1,
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("request fails", func() {
+		BeforeEach(func() {
+			mockClient.EXPECT().
+				Send(gomock.Any()).
+				Return(http.Result[searchResult]{}, errors.New(""))
+		})
+
+		It("returns error", func() {
+			_, err := as.DeveloperApps(DeveloperAppsInput{
+				Account:  Account{StoreFront: "143441"},
+				ArtistID: 1,
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("request returns bad status code", func() {
+		BeforeEach(func() {
+			mockClient.EXPECT().
+				Send(gomock.Any()).
+				Return(http.Result[searchResult]{StatusCode: 400}, nil)
+		})
+
+		It("returns error", func() {
+			_, err := as.DeveloperApps(DeveloperAppsInput{
+				Account:  Account{StoreFront: "143441"},
+				ArtistID: 1,
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("artist does not exist", func() {
+		BeforeEach(func() {
+			mockClient.EXPECT().
+				Send(gomock.Any()).
+				Return(http.Result[searchResult]{StatusCode: 200}, nil)
+		})
+
+		It("returns error", func() {
+			_, err := as.DeveloperApps(DeveloperAppsInput{
+				Account:  Account{StoreFront: "143441"},
+				ArtistID: 1,
+			})
+			Expect(err).To(MatchError(ErrDeveloperNotFound))
+		})
+	})
+
+	When("artist exists", func() {
+		var query url.Values
+
+		BeforeEach(func() {
+			mockClient.EXPECT().
+				Send(gomock.Any()).
+				DoAndReturn(func(req http.Request) (http.Result[searchResult], error) {
+					parsedURL, err := url.Parse(req.URL)
+					Expect(err).ToNot(HaveOccurred())
+
+					query = parsedURL.Query()
+
+					return http.Result[searchResult]{
+						StatusCode: 200,
+						Data: searchResult{
+							Count: 4,
+							Results: []App{
+								{ArtistID: 1, ArtistName: "Developer"},
+								{ID: 10, ArtistID: 1},
+								{ID: 11, ArtistID: 1},
+								{ID: 10, ArtistID: 1},
+							},
+						},
+					}, nil
+				})
+		})
+
+		It("returns the artist's apps across all platforms", func() {
+			out, err := as.DeveloperApps(DeveloperAppsInput{
+				Account:  Account{StoreFront: "143441"},
+				ArtistID: 1,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(query.Get("id")).To(Equal("1"))
+			Expect(query.Get("entity")).To(Equal("software,iPadSoftware,tvSoftware"))
+			Expect(query.Get("country")).To(Equal("US"))
+			Expect(out.ArtistID).To(Equal(int64(1)))
+			Expect(out.Apps).To(Equal([]App{
+				{ID: 10, ArtistID: 1},
+				{ID: 11, ArtistID: 1},
+			}))
+		})
+	})
+
+	When("resolving the seller name", func() {
+		var queries []url.Values
+
+		BeforeEach(func() {
+			queries = nil
+
+			mockClient.EXPECT().
+				Send(gomock.Any()).
+				DoAndReturn(func(req http.Request) (http.Result[searchResult], error) {
+					parsedURL, err := url.Parse(req.URL)
+					Expect(err).ToNot(HaveOccurred())
+
+					queries = append(queries, parsedURL.Query())
+
+					if parsedURL.Query().Has("term") {
+						return http.Result[searchResult]{
+							StatusCode: 200,
+							Data: searchResult{
+								Results: []App{
+									{ID: 20, ArtistID: 2, SellerName: "Other"},
+									{ID: 30, ArtistID: 3, SellerName: "Seller Inc."},
+								},
+							},
+						}, nil
+					}
+
+					return http.Result[searchResult]{
+						StatusCode: 200,
+						Data: searchResult{
+							Results: []App{{ArtistID: 3}, {ID: 30, ArtistID: 3}},
+						},
+					}, nil
+				}).
+				Times(2)
+		})
+
+		It("looks the apps up for the matching artist", func() {
+			out, err := as.DeveloperApps(DeveloperAppsInput{
+				Account:    Account{StoreFront: "143441"},
+				SellerName: "seller inc.",
+				Platform:   PlatformAppleTV,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(queries[0].Get("term")).To(Equal("seller inc."))
+			Expect(queries[1].Get("id")).To(Equal("3"))
+			Expect(queries[1].Get("entity")).To(Equal("tvSoftware"))
+			Expect(out.ArtistID).To(Equal(int64(3)))
+			Expect(out.Apps).To(Equal([]App{{ID: 30, ArtistID: 3}}))
+		})
+	})
+
+	When("no search result matches the seller name", func() {
+		BeforeEach(func() {
+			mockClient.EXPECT().
+				Send(gomock.Any()).
+				Return(http.Result[searchResult]{
+					StatusCode: 200,
+					Data:       searchResult{Results: []App{{ID: 20, ArtistID: 2, SellerName: "Other"}}},
+				}, nil)
+		})
+
+		It("returns error", func() {
+			_, err := as.DeveloperApps(DeveloperAppsInput{
+				Account:    Account{StoreFront: "143441"},
+				SellerName: "Seller Inc.",
+			})
+			Expect(err).To(MatchError(ErrDeveloperNotFound))
+		})
+	})
+})
@@ -2,15 +2,19 @@ package appstore
 
 import (
 	"archive/zip"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	gohttp "net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/majd/ipatool/v2/pkg/http"
-	"github.com/schollz/progressbar/v3"
+	"github.com/majd/ipatool/v2/pkg/progress"
 	"howett.net/plist"
 )
 
@@ -19,26 +23,44 @@ var (
 )
 
 type DownloadInput struct {
-	Account           Account
-	App               App
-	OutputPath        string
-	Progress          *progressbar.ProgressBar
+	Account    Account
+	App        App
+	OutputPath string
+	// Progress receives the progress of the download, if set.
+	Progress          progress.Progress
 	ExternalVersionID string
 	Platform          Platform
+	// Context cancels the transfer of the package when done. A cancelled transfer is resumed by
+	// the next download to the same destination.
+	Context context.Context
+	// FileNameTemplate renders the path of the package relative to the output path, which is then
+	// treated as a directory. Defaults to bundleID_appID_version.ipa in the output path.
+	FileNameTemplate *template.Template
+	// IfExists determines what happens when the destination already exists. Defaults to overwriting it.
+	IfExists ExistsPolicy
 }
 
 type DownloadOutput struct {
-	DestinationPath string
-	Sinfs           []Sinf
+	DestinationPath   string
+	Sinfs             []Sinf
+	BundleID          string
+	Version           string
+	ExternalVersionID string
+	// Skipped is true when the destination already existed and the policy skipped the download.
+	Skipped bool
 }
 
 func (t *appstore) Download(input DownloadInput) (DownloadOutput, error) {
-	macAddr, err := t.machine.MacAddress()
-	if err != nil {
-		return DownloadOutput{}, fmt.Errorf("failed to get mac address: %w", err)
+	if input.Progress == nil {
+		input.Progress = progress.Discard
 	}
 
-	guid := strings.ReplaceAll(strings.ToUpper(macAddr), ":", "")
+	input.Progress.SetPhase(progress.PhaseResolving)
+
+	guid, err := t.deviceGUID(input.Account)
+	if err != nil {
+		return DownloadOutput{}, err
+	}
 
 	externalVersionID := input.ExternalVersionID
 	if externalVersionID == "" && input.Platform == PlatformAppleTV {
@@ -67,15 +89,15 @@ func (t *appstore) Download(input DownloadInput) (DownloadOutput, error) {
 	}
 
 	if res.Data.FailureType != "" && res.Data.CustomerMessage != "" {
-		return DownloadOutput{}, NewErrorWithMetadata(fmt.Errorf("received error: %s", res.Data.CustomerMessage), res)
+		return DownloadOutput{}, newResponseError(fmt.Errorf("received error: %s", res.Data.CustomerMessage), res)
 	}
 
 	if res.Data.FailureType != "" {
-		return DownloadOutput{}, NewErrorWithMetadata(fmt.Errorf("received error: %s", res.Data.FailureType), res)
+		return DownloadOutput{}, newResponseError(fmt.Errorf("received error: %s", res.Data.FailureType), res)
 	}
 
 	if len(res.Data.Items) == 0 {
-		return DownloadOutput{}, NewErrorWithMetadata(errors.New("invalid response"), res)
+		return DownloadOutput{}, newResponseError(errors.New("invalid response"), res)
 	}
 
 	item := res.Data.Items[0]
@@ -87,39 +109,118 @@ func (t *appstore) Download(input DownloadInput) (DownloadOutput, error) {
 		version = fmt.Sprintf("%v", itemVersion)
 	}
 
-	destination, err := t.resolveDestinationPath(input.App, version, input.OutputPath)
+	data := fileNameData(input, item, version, externalVersionID)
+
+	var destination string
+
+	if input.FileNameTemplate != nil {
+		destination, err = t.renderDestinationPath(data, input.FileNameTemplate, input.OutputPath)
+	} else {
+		destination, err = t.resolveDestinationPath(input.App, version, input.OutputPath)
+	}
+
 	if err != nil {
 		return DownloadOutput{}, fmt.Errorf("failed to resolve destination path: %w", err)
 	}
 
+	destination, skip, err := t.applyExistsPolicy(destination, input.IfExists)
+	if err != nil {
+		return DownloadOutput{}, err
+	}
+
+	if skip {
+		return DownloadOutput{
+			DestinationPath:   destination,
+			BundleID:          data.BundleID,
+			Version:           version,
+			ExternalVersionID: data.ExternalVersionID,
+			Skipped:           true,
+		}, nil
+	}
+
 	tmpPath := fmt.Sprintf("%s.tmp", destination)
 
-	err = t.downloadFile(item.URL, tmpPath, input.Progress)
+	state, err := t.preparePartialDownload(tmpPath, newDownloadState(input, item, externalVersionID))
+	if err != nil {
+		return DownloadOutput{}, fmt.Errorf("failed to prepare download: %w", err)
+	}
+
+	input.Progress.SetPhase(progress.PhaseDownloading)
+
+	err = t.downloadFile(input.Context, item.URL, tmpPath, input.Progress, func(size int64, offset int64) error {
+		// The remaining data and the assembled package are both written to the destination directory.
+		err := t.checkFreeSpace(filepath.Dir(destination), size-offset+size)
+		if err != nil {
+			return err
+		}
+
+		state.Size = size
+
+		return t.writeDownloadState(downloadStatePath(tmpPath), state)
+	})
 	if err != nil {
 		return DownloadOutput{}, fmt.Errorf("failed to download file: %w", err)
 	}
 
-	err = t.applyPatches(item, input.Account, tmpPath, destination)
+	input.Progress.SetPhase(progress.PhaseVerifying)
+
+	err = t.verifyPartialDownload(tmpPath, state)
 	if err != nil {
-		return DownloadOutput{}, fmt.Errorf("failed to apply patches: %w", err)
+		return DownloadOutput{}, fmt.Errorf("failed to verify download: %w", err)
 	}
 
-	err = t.validatePackagePlatform(destination, input.Platform)
+	input.Progress.SetPhase(progress.PhasePatching)
+
+	err = t.assemblePackage(item, input, tmpPath, destination)
 	if err != nil {
-		return DownloadOutput{}, fmt.Errorf("failed to validate package platform: %w", err)
+		return DownloadOutput{}, err
 	}
 
-	err = t.os.Remove(fmt.Sprintf("%s.tmp", destination))
+	err = t.discardPartialDownload(tmpPath)
 	if err != nil {
-		return DownloadOutput{}, fmt.Errorf("failed to remove file: %w", err)
+		return DownloadOutput{}, err
 	}
 
 	return DownloadOutput{
-		DestinationPath: destination,
-		Sinfs:           item.Sinfs,
+		DestinationPath:   destination,
+		Sinfs:             item.Sinfs,
+		BundleID:          data.BundleID,
+		Version:           version,
+		ExternalVersionID: data.ExternalVersionID,
 	}, nil
 }
 
+// assemblePackage patches the downloaded file into a temporary file next to the destination, which is
+// renamed into place once complete, so that the destination never contains a partial package. The
+// temporary file is removed on failure, along with the downloaded file when it is not a valid package.
+func (t *appstore) assemblePackage(item downloadItemResult, input DownloadInput, src, dst string) error {
+	path := fmt.Sprintf("%s.partial", dst)
+
+	err := t.applyPatches(item, input.Account, src, path)
+	if err != nil {
+		_ = t.os.Remove(path)
+
+		return fmt.Errorf("failed to apply patches: %w", err)
+	}
+
+	err = t.validatePackagePlatform(path, input.Platform)
+	if err != nil {
+		_ = t.os.Remove(path)
+		_ = t.discardPartialDownload(src)
+
+		return fmt.Errorf("failed to validate package platform: %w", err)
+	}
+
+	err = t.os.Rename(path, dst)
+	if err != nil {
+		_ = t.os.Remove(path)
+
+		return fmt.Errorf("failed to move package into place: %w", err)
+	}
+
+	return nil
+}
+
 type platformPackageInfo struct {
 	SupportedPlatforms []string `plist:"CFBundleSupportedPlatforms,omitempty"`
 }
@@ -186,7 +287,18 @@ type downloadResult struct {
 	Items           []downloadItemResult `plist:"songList,omitempty"`
 }
 
-func (t *appstore) downloadFile(src, dst string, progress *progressbar.ProgressBar) error {
+func (r downloadResult) failure() (string, string) {
+	return r.FailureType, r.CustomerMessage
+}
+
+// downloadFile downloads the file to the destination. A partial download left at the destination, e.g. by
+// an interrupted process, is resumed with a range request. The optional start function is called with the
+// size of the file, or zero when unknown, and the size of the partial download before any data is written.
+func (t *appstore) downloadFile(ctx context.Context, src, dst string, reporter progress.Progress, start func(size int64, offset int64) error) error {
+	if reporter == nil {
+		reporter = progress.Discard
+	}
+
 	req, err := t.httpClient.NewRequest("GET", src, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
@@ -204,8 +316,25 @@ func (t *appstore) downloadFile(src, dst string, progress *progressbar.ProgressB
 		return fmt.Errorf("failed to get file info: %w", err)
 	}
 
+	var offset int64
+	if stat != nil {
+		offset = stat.Size()
+	}
+
+	// Transfers are not started while they are paused by the schedule of the limiter.
+	if t.limiter != nil {
+		err = t.limiter.WaitN(ctx, 0)
+		if err != nil {
+			return fmt.Errorf("failed to wait for the end of the pause: %w", err)
+		}
+	}
+
 	if req != nil && stat != nil {
-		req.Header.Add("range", fmt.Sprintf("bytes=%d-", stat.Size()))
+		req.Header.Add("range", fmt.Sprintf("bytes=%d-", offset))
+
+		if ctx != nil {
+			req = req.WithContext(ctx)
+		}
 	}
 
 	res, err := t.httpClient.Do(req)
@@ -214,24 +343,48 @@ func (t *appstore) downloadFile(src, dst string, progress *progressbar.ProgressB
 	}
 	defer res.Body.Close()
 
-	if progress != nil {
-		progress.ChangeMax64(res.ContentLength + stat.Size())
-		err = progress.Set64(stat.Size())
+	switch res.StatusCode {
+	case gohttp.StatusRequestedRangeNotSatisfiable:
+		// The partial download is already complete.
+		return nil
+	case gohttp.StatusOK:
+		// The server ignored the range and sends the whole file.
+		if offset > 0 {
+			err = file.Truncate(0)
+			if err != nil {
+				return fmt.Errorf("can not truncate file: %w", err)
+			}
 
-		if err != nil {
-			return fmt.Errorf("can not set bar progress: %w", err)
+			offset = 0
 		}
+	}
 
-		_, err = file.Seek(0, io.SeekEnd)
+	var size int64
+	if res.ContentLength >= 0 {
+		size = res.ContentLength + offset
+	}
+
+	if start != nil {
+		err = start(size, offset)
 		if err != nil {
-			return fmt.Errorf("can not seek file: %w", err)
+			return err
 		}
+	}
 
-		_, err = io.Copy(io.MultiWriter(file, progress), res.Body)
-	} else {
-		_, err = io.Copy(file, res.Body)
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("can not seek file: %w", err)
+	}
+
+	var body io.Reader = res.Body
+	if t.limiter != nil {
+		body = t.limiter.Reader(ctx, body)
 	}
 
+	reporter.SetTotal(size)
+	reporter.SetDone(offset)
+
+	_, err = io.Copy(io.MultiWriter(file, progress.Writer(reporter, offset)), body)
 	if err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
@@ -312,6 +465,31 @@ func (t *appstore) resolveDestinationPath(app App, version string, path string)
 	return path, nil
 }
 
+// renderDestinationPath renders the path of the package within the output directory and creates the
+// directories of the path.
+func (t *appstore) renderDestinationPath(data FileNameData, tmpl *template.Template, dir string) (string, error) {
+	file, err := renderFileName(tmpl, data)
+	if err != nil {
+		return "", err
+	}
+
+	if dir == "" {
+		dir, err = t.os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+
+	path := filepath.Join(dir, file)
+
+	err = t.os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	return path, nil
+}
+
 func (t *appstore) isDirectory(path string) (bool, error) {
 	info, err := t.os.Stat(path)
 	if err != nil && !os.IsNotExist(err) {
@@ -336,12 +514,28 @@ func (t *appstore) applyPatches(item downloadItemResult, acc Account, src, dst s
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
 	}
-	defer dstFile.Close()
 
-	dstZip := zip.NewWriter(dstFile)
-	defer dstZip.Close()
+	err = t.writePatches(item, acc, srcZip, dstFile)
+	closeErr := dstFile.Close()
 
-	err = t.replicateZip(srcZip, dstZip)
+	if err != nil {
+		return err
+	}
+
+	if closeErr != nil {
+		return fmt.Errorf("failed to close file: %w", closeErr)
+	}
+
+	return nil
+}
+
+// writePatches writes the patched package to the file. The zip writer is closed and the file synced
+// before returning, so that a failure to write the package, e.g. on a full disk, is reported instead of
+// leaving a truncated package behind.
+func (t *appstore) writePatches(item downloadItemResult, acc Account, srcZip *zip.ReadCloser, file *os.File) error {
+	dstZip := zip.NewWriter(file)
+
+	err := t.replicateZip(srcZip, dstZip)
 	if err != nil {
 		return fmt.Errorf("failed to replicate zip: %w", err)
 	}
@@ -351,6 +545,16 @@ func (t *appstore) applyPatches(item downloadItemResult, acc Account, src, dst s
 		return fmt.Errorf("failed to write metadata: %w", err)
 	}
 
+	err = dstZip.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write zip: %w", err)
+	}
+
+	err = file.Sync()
+	if err != nil {
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+
 	return nil
 }
 
@@ -2,6 +2,7 @@ package appstore
 
 import (
 	"archive/zip"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -9,13 +10,16 @@ import (
 	gohttp "net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/majd/ipatool/v2/pkg/http"
 	"github.com/majd/ipatool/v2/pkg/keychain"
+	"github.com/majd/ipatool/v2/pkg/progress"
 	"github.com/majd/ipatool/v2/pkg/util/machine"
 	"github.com/majd/ipatool/v2/pkg/util/operatingsystem"
+	"github.com/majd/ipatool/v2/pkg/util/ratelimit"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
@@ -378,6 +382,10 @@ var _ = Describe("AppStore (Download)", func() {
 				Getwd().
 				Return("", nil)
 
+			mockOS.EXPECT().
+				Stat("/unknown.ipa.tmp").
+				Return(nil, os.ErrNotExist)
+
 			mockMachine.EXPECT().
 				MacAddress().
 				Return("", nil)
@@ -488,6 +496,11 @@ var _ = Describe("AppStore (Download)", func() {
 						Body: io.NopCloser(strings.NewReader("ping")),
 					}, nil)
 
+				mockOS.EXPECT().
+					OpenFile("/unknown.ipa.tmp.state", gomock.Any(), gomock.Any()).
+					DoAndReturn(func(string, int, os.FileMode) (*os.File, error) {
+						return os.Create(filepath.Join(GinkgoT().TempDir(), "state"))
+					})
 			})
 
 			It("returns error", func() {
@@ -534,10 +547,20 @@ var _ = Describe("AppStore (Download)", func() {
 				NewRequest("GET", gomock.Any(), nil).
 				Return(&gohttp.Request{Header: map[string][]string{}}, nil)
 
+			mockOS.EXPECT().
+				Stat(gomock.Cond(func(path any) bool { return strings.HasSuffix(path.(string), ".tmp") })).
+				Return(nil, os.ErrNotExist)
+
 			mockOS.EXPECT().
 				OpenFile(gomock.Any(), gomock.Any(), gomock.Any()).
 				Return(testFile, nil)
 
+			mockOS.EXPECT().
+				OpenFile(gomock.Cond(func(path any) bool { return strings.HasSuffix(path.(string), ".state") }), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(string, int, os.FileMode) (*os.File, error) {
+					return os.Create(filepath.Join(GinkgoT().TempDir(), "state"))
+				})
+
 			mockOS.EXPECT().
 				Stat(gomock.Any()).
 				Return(&dummyFileInfo{}, nil)
@@ -559,6 +582,10 @@ var _ = Describe("AppStore (Download)", func() {
 				Getwd().
 				Return("", nil)
 
+			mockOS.EXPECT().
+				Remove("/xyz.ipa.partial").
+				Return(os.ErrNotExist)
+
 			_, err := as.Download(DownloadInput{})
 			Expect(err).To(HaveOccurred())
 
@@ -589,10 +616,18 @@ var _ = Describe("AppStore (Download)", func() {
 					Stat(gomock.Any()).
 					Return(nil, nil)
 
+				mockOS.EXPECT().
+					Rename(outputPath+".partial", outputPath).
+					DoAndReturn(os.Rename)
+
 				mockOS.EXPECT().
 					Remove(tmpFile.Name()).
 					Return(nil)
 
+				mockOS.EXPECT().
+					Remove(tmpFile.Name() + ".state").
+					Return(nil)
+
 				zipFile := zip.NewWriter(tmpFile)
 				w, err := zipFile.Create("Payload/Test.app/Info.plist")
 				Expect(err).ToNot(HaveOccurred())
@@ -663,4 +698,212 @@ var _ = Describe("AppStore (Download)", func() {
 			Expect(err.Error()).To(ContainSubstring("AppleTVOS"))
 		})
 	})
+
+	Describe("package assembly", func() {
+		var (
+			dir         string
+			src         string
+			destination string
+		)
+
+		writeArchive := func(path string, platforms []string) {
+			file, err := os.Create(path)
+			Expect(err).ToNot(HaveOccurred())
+			defer file.Close()
+
+			zipFile := zip.NewWriter(file)
+			w, err := zipFile.Create("Payload/Test.app/Info.plist")
+			Expect(err).ToNot(HaveOccurred())
+
+			info, err := plist.Marshal(map[string]interface{}{
+				"CFBundleSupportedPlatforms": platforms,
+			}, plist.BinaryFormat)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = w.Write(info)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(zipFile.Close()).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			dir = GinkgoT().TempDir()
+			src = filepath.Join(dir, "app.ipa.tmp")
+			destination = filepath.Join(dir, "app.ipa")
+			as = &appstore{os: operatingsystem.New()}
+		})
+
+		It("moves the package into place", func() {
+			writeArchive(src, []string{"AppleTVOS"})
+
+			err := as.(*appstore).assemblePackage(downloadItemResult{Metadata: map[string]interface{}{}}, DownloadInput{Platform: PlatformAppleTV}, src, destination)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(destination).To(BeAnExistingFile())
+			Expect(destination + ".partial").ToNot(BeAnExistingFile())
+		})
+
+		It("leaves the destination untouched when the package cannot be patched", func() {
+			Expect(os.WriteFile(src, []byte("corrupt"), 0600)).To(Succeed())
+			Expect(os.WriteFile(destination, []byte("existing"), 0600)).To(Succeed())
+
+			err := as.(*appstore).assemblePackage(downloadItemResult{Metadata: map[string]interface{}{}}, DownloadInput{}, src, destination)
+			Expect(err).To(HaveOccurred())
+			Expect(os.ReadFile(destination)).To(Equal([]byte("existing")))
+			Expect(destination + ".partial").ToNot(BeAnExistingFile())
+			Expect(src).To(BeAnExistingFile())
+		})
+
+		It("leaves the destination untouched when the package cannot be written", func() {
+			writeArchive(src, []string{"iPhoneOS"})
+			Expect(os.WriteFile(destination, []byte("existing"), 0600)).To(Succeed())
+
+			partial := destination + ".partial"
+			Expect(os.WriteFile(partial, nil, 0600)).To(Succeed())
+
+			as = &appstore{os: mockOS}
+
+			// Writes to a read-only file fail once the zip writer flushes its buffer when it is closed, like
+			// the writes to a full disk.
+			mockOS.EXPECT().
+				OpenFile(partial, gomock.Any(), gomock.Any()).
+				DoAndReturn(func(name string, flag int, perm os.FileMode) (*os.File, error) {
+					return os.Open(name)
+				})
+
+			mockOS.EXPECT().
+				Remove(partial).
+				DoAndReturn(os.Remove)
+
+			err := as.(*appstore).assemblePackage(downloadItemResult{Metadata: map[string]interface{}{}}, DownloadInput{}, src, destination)
+			Expect(err).To(MatchError(ContainSubstring("failed to write zip")))
+			Expect(os.ReadFile(destination)).To(Equal([]byte("existing")))
+			Expect(partial).ToNot(BeAnExistingFile())
+			Expect(src).To(BeAnExistingFile())
+		})
+
+		It("discards the download when the package is invalid", func() {
+			writeArchive(src, []string{"iPhoneOS"})
+
+			err := as.(*appstore).assemblePackage(downloadItemResult{Metadata: map[string]interface{}{}}, DownloadInput{Platform: PlatformAppleTV}, src, destination)
+			Expect(err).To(HaveOccurred())
+			Expect(destination).ToNot(BeAnExistingFile())
+			Expect(destination + ".partial").ToNot(BeAnExistingFile())
+			Expect(src).ToNot(BeAnExistingFile())
+		})
+	})
+
+	When("resuming a partial download", func() {
+		var (
+			path    string
+			request *gohttp.Request
+		)
+
+		BeforeEach(func() {
+			path = filepath.Join(GinkgoT().TempDir(), "app.ipa.tmp")
+			Expect(os.WriteFile(path, []byte("pa"), 0600)).To(Succeed())
+
+			request = &gohttp.Request{Header: map[string][]string{}}
+
+			mockHTTPClient.EXPECT().
+				NewRequest("GET", "https://example.com/app.ipa", nil).
+				Return(request, nil)
+
+			mockOS.EXPECT().
+				OpenFile(path, gomock.Any(), gomock.Any()).
+				DoAndReturn(os.OpenFile)
+
+			mockOS.EXPECT().
+				Stat(path).
+				DoAndReturn(os.Stat)
+		})
+
+		DescribeTable("writes the remaining data",
+			func(status int, body string, expected string) {
+				mockHTTPClient.EXPECT().
+					Do(gomock.Any()).
+					DoAndReturn(func(req *gohttp.Request) (*gohttp.Response, error) {
+						Expect(req.Header.Get("range")).To(Equal("bytes=2-"))
+
+						return &gohttp.Response{
+							StatusCode: status,
+							Body:       io.NopCloser(strings.NewReader(body)),
+						}, nil
+					})
+
+				err := as.(*appstore).downloadFile(context.Background(), "https://example.com/app.ipa", path, nil, nil)
+				Expect(err).ToNot(HaveOccurred())
+
+				data, err := os.ReadFile(path)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(data)).To(Equal(expected))
+			},
+			Entry("partial content", gohttp.StatusPartialContent, "ckage", "package"),
+			Entry("range ignored by the server", gohttp.StatusOK, "package", "package"),
+			Entry("download already complete", gohttp.StatusRequestedRangeNotSatisfiable, "", "pa"),
+		)
+
+		It("reports the progress of the transfer", func() {
+			mockHTTPClient.EXPECT().
+				Do(gomock.Any()).
+				Return(&gohttp.Response{
+					StatusCode:    gohttp.StatusPartialContent,
+					ContentLength: 5,
+					Body:          io.NopCloser(strings.NewReader("ckage")),
+				}, nil)
+
+			mockProgress := progress.NewMockProgress(ctrl)
+
+			gomock.InOrder(
+				mockProgress.EXPECT().SetTotal(int64(7)),
+				mockProgress.EXPECT().SetDone(int64(2)),
+				mockProgress.EXPECT().SetDone(int64(7)),
+			)
+
+			err := as.(*appstore).downloadFile(context.Background(), "https://example.com/app.ipa", path, mockProgress, nil)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		When("the rate is limited", func() {
+			var mockLimiter *ratelimit.MockLimiter
+
+			BeforeEach(func() {
+				mockLimiter = ratelimit.NewMockLimiter(ctrl)
+				as.(*appstore).limiter = mockLimiter
+			})
+
+			It("reads the data through the limiter", func() {
+				mockHTTPClient.EXPECT().
+					Do(gomock.Any()).
+					Return(&gohttp.Response{
+						StatusCode: gohttp.StatusPartialContent,
+						Body:       io.NopCloser(strings.NewReader("ckage")),
+					}, nil)
+
+				mockLimiter.EXPECT().
+					WaitN(gomock.Any(), 0).
+					Return(nil)
+
+				mockLimiter.EXPECT().
+					Reader(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, reader io.Reader) io.Reader {
+						return reader
+					})
+
+				err := as.(*appstore).downloadFile(context.Background(), "https://example.com/app.ipa", path, nil, nil)
+				Expect(err).ToNot(HaveOccurred())
+
+				data, err := os.ReadFile(path)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(data)).To(Equal("package"))
+			})
+
+			It("returns error when the pause is interrupted", func() {
+				mockLimiter.EXPECT().
+					WaitN(gomock.Any(), 0).
+					Return(context.Canceled)
+
+				err := as.(*appstore).downloadFile(context.Background(), "https://example.com/app.ipa", path, nil, nil)
+				Expect(err).To(MatchError(context.Canceled))
+			})
+		})
+	})
 })
@@ -3,7 +3,6 @@ package appstore
 import (
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/majd/ipatool/v2/pkg/http"
@@ -21,13 +20,11 @@ type GetVersionMetadataOutput struct {
 }
 
 func (t *appstore) GetVersionMetadata(input GetVersionMetadataInput) (GetVersionMetadataOutput, error) {
-	macAddr, err := t.machine.MacAddress()
+	guid, err := t.deviceGUID(input.Account)
 	if err != nil {
-		return GetVersionMetadataOutput{}, fmt.Errorf("failed to get mac address: %w", err)
+		return GetVersionMetadataOutput{}, err
 	}
 
-	guid := strings.ReplaceAll(strings.ToUpper(macAddr), ":", "")
-
 	req := t.getVersionMetadataRequest(input.Account, input.App, guid, input.VersionID)
 	res, err := t.downloadClient.Send(req)
 
@@ -44,15 +41,15 @@ func (t *appstore) GetVersionMetadata(input GetVersionMetadataInput) (GetVersion
 	}
 
 	if res.Data.FailureType != "" && res.Data.CustomerMessage != "" {
-		return GetVersionMetadataOutput{}, NewErrorWithMetadata(fmt.Errorf("received error: %s", res.Data.CustomerMessage), res)
+		return GetVersionMetadataOutput{}, newResponseError(fmt.Errorf("received error: %s", res.Data.CustomerMessage), res)
 	}
 
 	if res.Data.FailureType != "" {
-		return GetVersionMetadataOutput{}, NewErrorWithMetadata(fmt.Errorf("received error: %s", res.Data.FailureType), res)
+		return GetVersionMetadataOutput{}, newResponseError(fmt.Errorf("received error: %s", res.Data.FailureType), res)
 	}
 
 	if len(res.Data.Items) == 0 {
-		return GetVersionMetadataOutput{}, NewErrorWithMetadata(errors.New("invalid response"), res)
+		return GetVersionMetadataOutput{}, newResponseError(errors.New("invalid response"), res)
 	}
 
 	item := res.Data.Items[0]
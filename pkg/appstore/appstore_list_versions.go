@@ -3,7 +3,6 @@ package appstore
 import (
 	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/majd/ipatool/v2/pkg/http"
 )
@@ -19,13 +18,11 @@ type ListVersionsOutput struct {
 }
 
 func (t *appstore) ListVersions(input ListVersionsInput) (ListVersionsOutput, error) {
-	macAddr, err := t.machine.MacAddress()
+	guid, err := t.deviceGUID(input.Account)
 	if err != nil {
-		return ListVersionsOutput{}, fmt.Errorf("failed to get mac address: %w", err)
+		return ListVersionsOutput{}, err
 	}
 
-	guid := strings.ReplaceAll(strings.ToUpper(macAddr), ":", "")
-
 	req := t.listVersionsRequest(input.Account, input.App, guid)
 	res, err := t.downloadClient.Send(req)
 
@@ -42,15 +39,15 @@ func (t *appstore) ListVersions(input ListVersionsInput) (ListVersionsOutput, er
 	}
 
 	if res.Data.FailureType != "" && res.Data.CustomerMessage != "" {
-		return ListVersionsOutput{}, NewErrorWithMetadata(fmt.Errorf("received error: %s", res.Data.CustomerMessage), res)
+		return ListVersionsOutput{}, newResponseError(fmt.Errorf("received error: %s", res.Data.CustomerMessage), res)
 	}
 
 	if res.Data.FailureType != "" {
-		return ListVersionsOutput{}, NewErrorWithMetadata(fmt.Errorf("received error: %s", res.Data.FailureType), res)
+		return ListVersionsOutput{}, newResponseError(fmt.Errorf("received error: %s", res.Data.FailureType), res)
 	}
 
 	if len(res.Data.Items) == 0 {
-		return ListVersionsOutput{}, NewErrorWithMetadata(errors.New("invalid response"), res)
+		return ListVersionsOutput{}, newResponseError(errors.New("invalid response"), res)
 	}
 
 	item := res.Data.Items[0]
@@ -62,6 +62,26 @@ var _ = Describe("AppStore (ListVersions)", func() {
 		})
 	})
 
+	When("account carries a device GUID", func() {
+		const testGUID = "AABBCCDDEEFF"
+
+		BeforeEach(func() {
+			mockDownloadClient.EXPECT().
+				Send(gomock.Any()).
+				Do(func(req http.Request) {
+					Expect(req.URL).To(HaveSuffix("?guid=" + testGUID))
+				}).
+				Return(http.Result[downloadResult]{}, errors.New(""))
+		})
+
+		It("does not derive the GUID from the MAC address", func() {
+			_, err := as.ListVersions(ListVersionsInput{
+				Account: Account{GUID: testGUID},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	When("request uses a custom pod", func() {
 		const (
 			testPod  = "42"
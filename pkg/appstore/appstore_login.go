@@ -63,6 +63,10 @@ type loginResult struct {
 	PasswordToken       string             `plist:"passwordToken,omitempty"`
 }
 
+func (r loginResult) failure() (string, string) {
+	return r.FailureType, r.CustomerMessage
+}
+
 func (t *appstore) login(email, password, authCode, guid, endpoint string) (Account, error) {
 	redirect := ""
 
@@ -88,17 +92,17 @@ func (t *appstore) login(email, password, authCode, guid, endpoint string) (Acco
 	}
 
 	if retry {
-		return Account{}, NewErrorWithMetadata(errors.New("too many attempts"), res)
+		return Account{}, newResponseError(errors.New("too many attempts"), res)
 	}
 
 	sf, err := res.GetHeader(HTTPHeaderStoreFront)
 	if err != nil {
-		return Account{}, NewErrorWithMetadata(fmt.Errorf("failed to get storefront header: %w", err), res)
+		return Account{}, newResponseError(fmt.Errorf("failed to get storefront header: %w", err), res)
 	}
 
 	pod, err := res.GetHeader(HTTPHeaderPod)
 	if err != nil && !errors.Is(err, http.ErrHeaderNotFound) {
-		return Account{}, NewErrorWithMetadata(fmt.Errorf("failed to get pod header: %w", err), res)
+		return Account{}, newResponseError(fmt.Errorf("failed to get pod header: %w", err), res)
 	}
 
 	addr := res.Data.Account.Address
@@ -143,15 +147,15 @@ func (t *appstore) parseLoginResponse(res *http.Result[loginResult], attempt int
 	} else if res.Data.FailureType == "" && authCode == "" && res.Data.CustomerMessage == CustomerMessageBadLogin {
 		err = ErrAuthCodeRequired
 	} else if res.Data.FailureType == "" && res.Data.CustomerMessage == CustomerMessageAccountDisabled {
-		err = NewErrorWithMetadata(errors.New("account is disabled"), res)
+		err = newResponseError(errors.New("account is disabled"), *res)
 	} else if res.Data.FailureType != "" {
 		if res.Data.CustomerMessage != "" {
-			err = NewErrorWithMetadata(errors.New(res.Data.CustomerMessage), res)
+			err = newResponseError(errors.New(res.Data.CustomerMessage), *res)
 		} else {
-			err = NewErrorWithMetadata(errors.New("something went wrong"), res)
+			err = newResponseError(errors.New("something went wrong"), *res)
 		}
 	} else if res.StatusCode != gohttp.StatusOK || res.Data.PasswordToken == "" || res.Data.DirectoryServicesID == "" {
-		err = NewErrorWithMetadata(errors.New("something went wrong"), res)
+		err = newResponseError(errors.New("something went wrong"), *res)
 	}
 
 	return retry, redirect, err
@@ -9,10 +9,16 @@ import (
 	"github.com/majd/ipatool/v2/pkg/http"
 )
 
+var (
+	ErrAppNotFound = errors.New("app not found")
+)
+
 type LookupInput struct {
 	Account  Account
 	BundleID string
 	Platform Platform
+	// Country overrides the country derived from the account's store front.
+	Country string
 }
 
 type LookupOutput struct {
@@ -20,7 +26,7 @@ type LookupOutput struct {
 }
 
 func (t *appstore) Lookup(input LookupInput) (LookupOutput, error) {
-	countryCode, err := countryCodeFromStoreFront(input.Account.StoreFront)
+	countryCode, err := resolveCountryCode(input.Country, input.Account.StoreFront)
 	if err != nil {
 		return LookupOutput{}, fmt.Errorf("failed to resolve the country code: %w", err)
 	}
@@ -36,11 +42,11 @@ func (t *appstore) Lookup(input LookupInput) (LookupOutput, error) {
 	}
 
 	if res.StatusCode != gohttp.StatusOK {
-		return LookupOutput{}, NewErrorWithMetadata(errors.New("invalid response"), res)
+		return LookupOutput{}, newResponseError(errors.New("invalid response"), res)
 	}
 
 	if len(res.Data.Results) == 0 {
-		return LookupOutput{}, errors.New("app not found")
+		return LookupOutput{}, ErrAppNotFound
 	}
 
 	return LookupOutput{
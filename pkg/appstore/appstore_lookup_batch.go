@@ -0,0 +1,137 @@
+package appstore
+
+import (
+	"errors"
+	"fmt"
+	gohttp "net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/majd/ipatool/v2/pkg/http"
+)
+
+const (
+	defaultLookupChunkSize = 100
+)
+
+type LookupBatchInput struct {
+	Account   Account
+	AppIDs    []int64
+	BundleIDs []string
+	Platform  Platform
+	// Country overrides the country derived from the account's store front.
+	Country string
+	// ChunkSize is the maximum number of identifiers sent in a single request.
+	ChunkSize int
+}
+
+type LookupBatchOutput struct {
+	Apps              []App
+	NotFoundAppIDs    []int64
+	NotFoundBundleIDs []string
+}
+
+// LookupBatch looks up several apps by app ID and/or bundle identifier, splitting large lists into
+// multiple requests. Identifiers without a match are reported in the output rather than as an error.
+func (t *appstore) LookupBatch(input LookupBatchInput) (LookupBatchOutput, error) {
+	countryCode, err := resolveCountryCode(input.Country, input.Account.StoreFront)
+	if err != nil {
+		return LookupBatchOutput{}, fmt.Errorf("failed to resolve the country code: %w", err)
+	}
+
+	chunkSize := input.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultLookupChunkSize
+	}
+
+	appIDs := make([]string, len(input.AppIDs))
+	for i, id := range input.AppIDs {
+		appIDs[i] = strconv.FormatInt(id, 10)
+	}
+
+	var (
+		apps []App
+		seen = map[int64]bool{}
+	)
+
+	for _, query := range []struct {
+		param  string
+		values []string
+	}{
+		{param: "id", values: appIDs},
+		{param: "bundleId", values: input.BundleIDs},
+	} {
+		for start := 0; start < len(query.values); start += chunkSize {
+			end := min(start+chunkSize, len(query.values))
+
+			results, err := t.lookupChunk(query.param, query.values[start:end], countryCode, input.Platform)
+			if err != nil {
+				return LookupBatchOutput{}, err
+			}
+
+			for _, app := range results {
+				if seen[app.ID] {
+					continue
+				}
+
+				seen[app.ID] = true
+				apps = append(apps, app)
+			}
+		}
+	}
+
+	output := LookupBatchOutput{Apps: apps}
+
+	for _, id := range input.AppIDs {
+		if !seen[id] {
+			output.NotFoundAppIDs = append(output.NotFoundAppIDs, id)
+		}
+	}
+
+	for _, bundleID := range input.BundleIDs {
+		if !containsBundleID(apps, bundleID) {
+			output.NotFoundBundleIDs = append(output.NotFoundBundleIDs, bundleID)
+		}
+	}
+
+	return output, nil
+}
+
+func (t *appstore) lookupChunk(param string, values []string, countryCode string, platform Platform) ([]App, error) {
+	entity, err := platform.lookupEntity()
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("entity", entity)
+	params.Add("media", "software")
+	params.Add(param, strings.Join(values, ","))
+	params.Add("country", countryCode)
+
+	res, err := t.searchClient.Send(http.Request{
+		URL:            fmt.Sprintf("https://%s%s?%s", iTunesAPIDomain, iTunesAPIPathLookup, params.Encode()),
+		Method:         http.MethodGET,
+		ResponseFormat: http.ResponseFormatJSON,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	if res.StatusCode != gohttp.StatusOK {
+		return nil, newResponseError(errors.New("invalid response"), res)
+	}
+
+	return res.Data.Results, nil
+}
+
+func containsBundleID(apps []App, bundleID string) bool {
+	for _, app := range apps {
+		if strings.EqualFold(app.BundleID, bundleID) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,147 @@
+package appstore
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/majd/ipatool/v2/pkg/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("AppStore (LookupBatch)", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockClient *http.MockClient[searchResult]
+		as         AppStore
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = http.NewMockClient[searchResult](ctrl)
+		as = &appstore{
+			searchClient: mockClient,
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	When("store front is invalid", func() {
+		It("returns error", func() {
+			_, err := as.LookupBatch(LookupBatchInput{
+				Account: Account{StoreFront: "xyz"},
+				AppIDs:  []int64{1},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("request fails", func() {
+		BeforeEach(func() {
+			mockClient.EXPECT().
+				Send(gomock.Any()).
+				Return(http.Result[searchResult]{}, errors.New(""))
+		})
+
+		It("returns error", func() {
+			_, err := as.LookupBatch(LookupBatchInput{
+				Account: Account{StoreFront: "143441"},
+				AppIDs:  []int64{1},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("request returns bad status code", func() {
+		BeforeEach(func() {
+			mockClient.EXPECT().
+				Send(gomock.Any()).
+				Return(http.Result[searchResult]{StatusCode: 400}, nil)
+		})
+
+		It("returns error", func() {
+			_, err := as.LookupBatch(LookupBatchInput{
+				Account:   Account{StoreFront: "143441"},
+				BundleIDs: []string{"app.bundle.id"},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("looking up app IDs and bundle identifiers", func() {
+		var queries []url.Values
+
+		BeforeEach(func() {
+			queries = nil
+
+			mockClient.EXPECT().
+				Send(gomock.Any()).
+				DoAndReturn(func(req http.Request) (http.Result[searchResult], error) {
+					parsedURL, err := url.Parse(req.URL)
+					Expect(err).ToNot(HaveOccurred())
+
+					query := parsedURL.Query()
+					queries = append(queries, query)
+
+					var results []App
+
+					for _, id := range strings.Split(query.Get("id"), ",") {
+						if id == "1" || id == "2" {
+							appID, err := strconv.ParseInt(id, 10, 64)
+							Expect(err).ToNot(HaveOccurred())
+
+							results = append(results, App{ID: appID, BundleID: "app.bundle.id" + id})
+						}
+					}
+
+					for _, bundleID := range strings.Split(query.Get("bundleId"), ",") {
+						if bundleID == "app.bundle.id2" {
+							results = append(results, App{ID: 2, BundleID: "app.bundle.id2"})
+						}
+
+						if bundleID == "APP.BUNDLE.ID4" {
+							results = append(results, App{ID: 4, BundleID: "app.bundle.id4"})
+						}
+					}
+
+					return http.Result[searchResult]{
+						StatusCode: 200,
+						Data:       searchResult{Count: len(results), Results: results},
+					}, nil
+				}).
+				Times(4)
+		})
+
+		It("chunks requests and reports missing identifiers", func() {
+			out, err := as.LookupBatch(LookupBatchInput{
+				Account:   Account{StoreFront: "143441"},
+				AppIDs:    []int64{1, 2, 3},
+				BundleIDs: []string{"app.bundle.id2", "APP.BUNDLE.ID4", "app.bundle.id5"},
+				Country:   "de",
+				ChunkSize: 2,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(queries).To(HaveLen(4))
+			Expect(queries[0].Get("id")).To(Equal("1,2"))
+			Expect(queries[1].Get("id")).To(Equal("3"))
+			Expect(queries[2].Get("bundleId")).To(Equal("app.bundle.id2,APP.BUNDLE.ID4"))
+			Expect(queries[3].Get("bundleId")).To(Equal("app.bundle.id5"))
+			Expect(queries[0].Get("country")).To(Equal("DE"))
+			Expect(queries[0].Has("limit")).To(BeFalse())
+
+			Expect(out.Apps).To(Equal([]App{
+				{ID: 1, BundleID: "app.bundle.id1"},
+				{ID: 2, BundleID: "app.bundle.id2"},
+				{ID: 4, BundleID: "app.bundle.id4"},
+			}))
+			Expect(out.NotFoundAppIDs).To(Equal([]int64{3}))
+			Expect(out.NotFoundBundleIDs).To(Equal([]string{"app.bundle.id5"}))
+		})
+	})
+})
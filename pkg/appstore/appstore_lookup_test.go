@@ -109,6 +109,41 @@ var _ = Describe("AppStore (Lookup)", func() {
 		})
 	})
 
+	When("country is specified", func() {
+		BeforeEach(func() {
+			mockClient.EXPECT().
+				Send(gomock.Any()).
+				Do(func(req http.Request) {
+					parsedURL, err := url.Parse(req.URL)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(parsedURL.Query().Get("country")).To(Equal("DE"))
+				}).
+				Return(http.Result[searchResult]{}, errors.New("request error"))
+		})
+
+		It("queries the specified store front", func() {
+			_, err := as.Lookup(LookupInput{
+				Account: Account{
+					StoreFront: "143441",
+				},
+				Country: "de",
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("country is not supported", func() {
+		It("returns error", func() {
+			_, err := as.Lookup(LookupInput{
+				Account: Account{
+					StoreFront: "143441",
+				},
+				Country: "XX",
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	When("store front is invalid", func() {
 		It("returns error", func() {
 			_, err := as.Lookup(LookupInput{
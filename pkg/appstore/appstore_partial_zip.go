@@ -3,6 +3,7 @@ package appstore
 import (
 	"archive/zip"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -13,6 +14,7 @@ import (
 	"time"
 
 	apphttp "github.com/majd/ipatool/v2/pkg/http"
+	"github.com/majd/ipatool/v2/pkg/util/ratelimit"
 	"howett.net/plist"
 )
 
@@ -35,6 +37,8 @@ type httpRangeReaderAt struct {
 	client apphttp.Client[interface{}]
 	url    string
 	size   int64
+	// limiter limits the rate of the reads, if set.
+	limiter ratelimit.Limiter
 }
 
 func newHTTPRangeReaderAt(client apphttp.Client[interface{}], url string) (*httpRangeReaderAt, int64, error) {
@@ -150,7 +154,12 @@ func (r *httpRangeReaderAt) ReadAt(p []byte, off int64) (int, error) {
 
 	readLength := int(rangeEnd-off) + 1
 
-	n, err := io.ReadFull(res.Body, p[:readLength])
+	var body io.Reader = res.Body
+	if r.limiter != nil {
+		body = r.limiter.Reader(context.Background(), body)
+	}
+
+	n, err := io.ReadFull(body, p[:readLength])
 	if err == io.ErrUnexpectedEOF || err == io.EOF {
 		return n, io.EOF
 	}
@@ -172,6 +181,8 @@ func (t *appstore) readVersionMetadataFromIPA(url string) (versionMetadata, erro
 		return versionMetadata{}, err
 	}
 
+	reader.limiter = t.limiter
+
 	zipReader, err := zip.NewReader(reader, size)
 	if err != nil {
 		return versionMetadata{}, fmt.Errorf("failed to open zip reader: %w", err)
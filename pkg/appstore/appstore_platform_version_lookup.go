@@ -77,16 +77,16 @@ func (t *appstore) lookupLatestExternalVersionID(acc Account, app App, platform
 	}
 
 	if res.StatusCode != gohttp.StatusOK {
-		return "", NewErrorWithMetadata(errors.New("platform version lookup request failed"), res)
+		return "", newResponseError(errors.New("platform version lookup request failed"), res)
 	}
 
 	item, ok := res.Data.Results[strconv.FormatInt(app.ID, 10)]
 	if !ok {
-		return "", NewErrorWithMetadata(errors.New("platform version lookup returned no app"), res)
+		return "", newResponseError(errors.New("platform version lookup returned no app"), res)
 	}
 
 	if len(item.Offers) == 0 {
-		return "", NewErrorWithMetadata(errors.New("platform version lookup returned no offers"), res)
+		return "", newResponseError(errors.New("platform version lookup returned no offers"), res)
 	}
 
 	offer := item.Offers[0]
@@ -100,7 +100,7 @@ func (t *appstore) lookupLatestExternalVersionID(acc Account, app App, platform
 	}
 
 	if externalVersionID == "" {
-		return "", NewErrorWithMetadata(errors.New("platform version lookup returned no external version id"), res)
+		return "", newResponseError(errors.New("platform version lookup returned no external version id"), res)
 	}
 
 	return externalVersionID, nil
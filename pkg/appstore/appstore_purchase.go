@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	gohttp "net/http"
-	"strings"
 
 	"github.com/majd/ipatool/v2/pkg/http"
 )
@@ -22,13 +21,11 @@ type PurchaseInput struct {
 }
 
 func (t *appstore) Purchase(input PurchaseInput) error {
-	macAddr, err := t.machine.MacAddress()
+	guid, err := t.deviceGUID(input.Account)
 	if err != nil {
-		return fmt.Errorf("failed to get mac address: %w", err)
+		return err
 	}
 
-	guid := strings.ReplaceAll(strings.ToUpper(macAddr), ":", "")
-
 	if input.App.Price > 0 {
 		return errors.New("purchasing paid apps is not supported")
 	}
@@ -57,6 +54,10 @@ type purchaseResult struct {
 	Status          int    `plist:"status,omitempty"`
 }
 
+func (r purchaseResult) failure() (string, string) {
+	return r.FailureType, r.CustomerMessage
+}
+
 func (t *appstore) purchaseWithParams(acc Account, app App, guid string, pricingParameters string) error {
 	req := t.purchaseRequest(acc, app, acc.StoreFront, guid, pricingParameters)
 	res, err := t.purchaseClient.Send(req)
@@ -85,11 +86,11 @@ func (t *appstore) purchaseWithParams(acc Account, app App, guid string, pricing
 	}
 
 	if res.Data.FailureType != "" && res.Data.CustomerMessage != "" {
-		return NewErrorWithMetadata(errors.New(res.Data.CustomerMessage), res)
+		return newResponseError(errors.New(res.Data.CustomerMessage), res)
 	}
 
 	if res.Data.FailureType != "" {
-		return NewErrorWithMetadata(errors.New("something went wrong"), res)
+		return newResponseError(errors.New("something went wrong"), res)
 	}
 
 	if res.StatusCode == gohttp.StatusInternalServerError {
@@ -97,7 +98,7 @@ func (t *appstore) purchaseWithParams(acc Account, app App, guid string, pricing
 	}
 
 	if res.Data.JingleDocType != "purchaseSuccess" || res.Data.Status != 0 {
-		return NewErrorWithMetadata(errors.New("failed to purchase app"), res)
+		return newResponseError(errors.New("failed to purchase app"), res)
 	}
 
 	return nil
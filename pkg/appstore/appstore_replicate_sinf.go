@@ -30,16 +30,57 @@ func (t *appstore) ReplicateSinf(input ReplicateSinfInput) error {
 		return errors.New("failed to open zip reader")
 	}
 
+	// The package is rewritten to a temporary file which replaces it once complete.
 	tmpPath := fmt.Sprintf("%s.tmp", input.PackagePath)
-	tmpFile, err := t.os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY, 0644)
+	tmpFile, err := t.os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 
 	if err != nil {
+		zipReader.Close()
+
 		return fmt.Errorf("failed to open file: %w", err)
 	}
 
-	zipWriter := zip.NewWriter(tmpFile)
+	err = t.writeSinfs(zipReader, tmpFile, input.Sinfs)
+
+	// The original file must be closed before it is replaced on Windows.
+	zipReader.Close()
+
+	if err != nil {
+		_ = t.os.Remove(tmpPath)
+
+		return err
+	}
+
+	err = t.os.Rename(tmpPath, input.PackagePath)
+	if err != nil {
+		_ = t.os.Remove(tmpPath)
+
+		return fmt.Errorf("failed to replace original file: %w", err)
+	}
+
+	return nil
+}
+
+// writeSinfs writes the package with the replicated sinfs to the file and closes it.
+func (t *appstore) writeSinfs(zipReader *zip.ReadCloser, file *os.File, sinfs []Sinf) error {
+	err := t.writeSinfsZip(zipReader, file, sinfs)
+	closeErr := file.Close()
 
-	err = t.replicateZip(zipReader, zipWriter)
+	if err != nil {
+		return err
+	}
+
+	if closeErr != nil {
+		return fmt.Errorf("failed to close file: %w", closeErr)
+	}
+
+	return nil
+}
+
+func (t *appstore) writeSinfsZip(zipReader *zip.ReadCloser, file *os.File, sinfs []Sinf) error {
+	zipWriter := zip.NewWriter(file)
+
+	err := t.replicateZip(zipReader, zipWriter)
 	if err != nil {
 		return fmt.Errorf("failed to replicate zip: %w", err)
 	}
@@ -60,27 +101,18 @@ func (t *appstore) ReplicateSinf(input ReplicateSinfInput) error {
 	}
 
 	if manifest != nil {
-		err = t.replicateSinfFromManifest(*manifest, zipWriter, input.Sinfs, bundleName)
+		err = t.replicateSinfFromManifest(*manifest, zipWriter, sinfs, bundleName)
 	} else {
-		err = t.replicateSinfFromInfo(*info, zipWriter, input.Sinfs, bundleName)
+		err = t.replicateSinfFromInfo(*info, zipWriter, sinfs, bundleName)
 	}
 
 	if err != nil {
 		return fmt.Errorf("failed to replicate sinf: %w", err)
 	}
 
-	zipReader.Close()
-	zipWriter.Close()
-	tmpFile.Close()
-
-	err = t.os.Remove(input.PackagePath)
-	if err != nil {
-		return fmt.Errorf("failed to remove original file: %w", err)
-	}
-
-	err = t.os.Rename(tmpPath, input.PackagePath)
+	err = zipWriter.Close()
 	if err != nil {
-		return fmt.Errorf("failed to remove original file: %w", err)
+		return fmt.Errorf("failed to write zip: %w", err)
 	}
 
 	return nil
@@ -74,10 +74,6 @@ var _ = Describe("AppStore (ReplicateSinf)", func() {
 				OpenFile(gomock.Any(), gomock.Any(), gomock.Any()).
 				DoAndReturn(os.OpenFile)
 
-			mockOS.EXPECT().
-				Remove(testFile.Name()).
-				Return(nil)
-
 			mockOS.EXPECT().
 				Rename(fmt.Sprintf("%s.tmp", testFile.Name()), testFile.Name()).
 				Return(nil)
@@ -138,10 +134,6 @@ var _ = Describe("AppStore (ReplicateSinf)", func() {
 				OpenFile(gomock.Any(), gomock.Any(), gomock.Any()).
 				DoAndReturn(os.OpenFile)
 
-			mockOS.EXPECT().
-				Remove(testFile.Name()).
-				Return(nil)
-
 			mockOS.EXPECT().
 				Rename(fmt.Sprintf("%s.tmp", testFile.Name()), testFile.Name()).
 				Return(nil)
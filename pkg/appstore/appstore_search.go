@@ -10,25 +10,51 @@ import (
 	"github.com/majd/ipatool/v2/pkg/http"
 )
 
+const (
+	// maxSearchLimit is the maximum number of results returned by the iTunes search API.
+	maxSearchLimit = 200
+)
+
 type SearchInput struct {
 	Account  Account
 	Term     string
 	Limit    int64
 	Platform Platform
+	// Country overrides the country derived from the account's store front.
+	Country string
+	// Offset is the number of matching results to skip.
+	Offset int64
+	Filter SearchFilter
+	Sort   SearchSort
+	// Descending reverses the sort order.
+	Descending bool
 }
 
 type SearchOutput struct {
+	// Count is the number of returned results.
 	Count   int
 	Results []App
+	// Total is the number of results matching the filter, before pagination. It is a lower bound, as the
+	// App Store returns at most 200 results per search.
+	Total int
+	// HasMore reports whether results beyond the requested page are available.
+	HasMore bool
 }
 
 func (t *appstore) Search(input SearchInput) (SearchOutput, error) {
-	countryCode, err := countryCodeFromStoreFront(input.Account.StoreFront)
+	countryCode, err := resolveCountryCode(input.Country, input.Account.StoreFront)
 	if err != nil {
 		return SearchOutput{}, fmt.Errorf("country code is invalid: %w", err)
 	}
 
-	request, err := t.searchRequest(input.Term, countryCode, input.Limit, input.Platform)
+	if input.Offset < 0 {
+		return SearchOutput{}, errors.New("offset must not be negative")
+	}
+
+	// The maximum number of results is always fetched: filtering and sorting only make sense across the
+	// whole result set, duplicates would leave pages short, and the total and whether more results are
+	// available cannot be known from a single page. A non-positive limit returns every result.
+	request, err := t.searchRequest(input.Term, countryCode, maxSearchLimit, input.Platform)
 	if err != nil {
 		return SearchOutput{}, fmt.Errorf("failed to create search request: %w", err)
 	}
@@ -39,12 +65,24 @@ func (t *appstore) Search(input SearchInput) (SearchOutput, error) {
 	}
 
 	if res.StatusCode != gohttp.StatusOK {
-		return SearchOutput{}, NewErrorWithMetadata(errors.New("request failed"), res)
+		return SearchOutput{}, newResponseError(errors.New("request failed"), res)
+	}
+
+	apps := filterApps(res.Data.Results, input.Filter)
+	sortApps(apps, input.Sort, input.Descending)
+
+	start := min(int(input.Offset), len(apps))
+	end := len(apps)
+
+	if input.Limit > 0 {
+		end = min(start+int(input.Limit), len(apps))
 	}
 
 	return SearchOutput{
-		Count:   res.Data.Count,
-		Results: res.Data.Results,
+		Count:   end - start,
+		Results: apps[start:end],
+		Total:   len(apps),
+		HasMore: end < len(apps),
 	}, nil
 }
 
@@ -3,6 +3,7 @@ package appstore
 import (
 	"errors"
 	"net/url"
+	"strconv"
 
 	"github.com/majd/ipatool/v2/pkg/http"
 	. "github.com/onsi/ginkgo/v2"
@@ -100,6 +101,41 @@ var _ = Describe("AppStore (Search)", func() {
 		})
 	})
 
+	When("country is specified", func() {
+		BeforeEach(func() {
+			mockClient.EXPECT().
+				Send(gomock.Any()).
+				Do(func(req http.Request) {
+					parsedURL, err := url.Parse(req.URL)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(parsedURL.Query().Get("country")).To(Equal("DE"))
+				}).
+				Return(http.Result[searchResult]{}, errors.New("request error"))
+		})
+
+		It("searches the specified store front", func() {
+			_, err := as.Search(SearchInput{
+				Account: Account{
+					StoreFront: "143441",
+				},
+				Country: "DE",
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("country is not supported", func() {
+		It("returns error", func() {
+			_, err := as.Search(SearchInput{
+				Account: Account{
+					StoreFront: "143441",
+				},
+				Country: "XX",
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	When("store front is invalid", func() {
 		It("returns error", func() {
 			_, err := as.Search(SearchInput{
@@ -146,4 +182,100 @@ var _ = Describe("AppStore (Search)", func() {
 			Expect(err).To(HaveOccurred())
 		})
 	})
+
+	When("paginating over duplicated results", func() {
+		var query url.Values
+
+		BeforeEach(func() {
+			mockClient.EXPECT().
+				Send(gomock.Any()).
+				DoAndReturn(func(req http.Request) (http.Result[searchResult], error) {
+					parsedURL, err := url.Parse(req.URL)
+					Expect(err).ToNot(HaveOccurred())
+
+					query = parsedURL.Query()
+
+					limit, err := strconv.Atoi(query.Get("limit"))
+					Expect(err).ToNot(HaveOccurred())
+
+					results := []App{{ID: 1}, {ID: 2}, {ID: 1}, {ID: 3}, {ID: 4}}
+					results = results[:min(limit, len(results))]
+
+					return http.Result[searchResult]{
+						StatusCode: 200,
+						Data: searchResult{
+							Count:   len(results),
+							Results: results,
+						},
+					}, nil
+				})
+		})
+
+		It("returns the requested page of unique results", func() {
+			out, err := as.Search(SearchInput{
+				Account: Account{StoreFront: "143441"},
+				Limit:   2,
+				Offset:  1,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(query.Get("limit")).To(Equal("200"))
+			Expect(out.Results).To(Equal([]App{{ID: 2}, {ID: 3}}))
+			Expect(out.Count).To(Equal(2))
+			Expect(out.Total).To(Equal(4))
+			Expect(out.HasMore).To(BeTrue())
+		})
+	})
+
+	When("filtering and sorting", func() {
+		var query url.Values
+
+		BeforeEach(func() {
+			mockClient.EXPECT().
+				Send(gomock.Any()).
+				DoAndReturn(func(req http.Request) (http.Result[searchResult], error) {
+					parsedURL, err := url.Parse(req.URL)
+					Expect(err).ToNot(HaveOccurred())
+
+					query = parsedURL.Query()
+
+					return http.Result[searchResult]{
+						StatusCode: 200,
+						Data: searchResult{
+							Results: []App{
+								{ID: 1, Price: 0, AverageUserRating: 4},
+								{ID: 2, Price: 1.99, AverageUserRating: 5},
+								{ID: 3, Price: 0, AverageUserRating: 4.5},
+							},
+						},
+					}, nil
+				})
+		})
+
+		It("fetches the maximum number of results", func() {
+			out, err := as.Search(SearchInput{
+				Account:    Account{StoreFront: "143441"},
+				Limit:      5,
+				Filter:     SearchFilter{FreeOnly: true},
+				Sort:       SearchSortRating,
+				Descending: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(query.Get("limit")).To(Equal("200"))
+			Expect(out.Results).To(Equal([]App{
+				{ID: 3, Price: 0, AverageUserRating: 4.5},
+				{ID: 1, Price: 0, AverageUserRating: 4},
+			}))
+			Expect(out.HasMore).To(BeFalse())
+		})
+	})
+
+	When("offset is negative", func() {
+		It("returns error", func() {
+			_, err := as.Search(SearchInput{
+				Account: Account{StoreFront: "143441"},
+				Offset:  -1,
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
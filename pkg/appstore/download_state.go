@@ -0,0 +1,154 @@
+package appstore
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// downloadState binds a partial download to the package being downloaded. It is stored next to the
+// partial download, so that partial downloads of other apps or versions are discarded instead of resumed.
+type downloadState struct {
+	AppID             int64  `json:"appID"`
+	ExternalVersionID string `json:"externalVersionID,omitempty"`
+	// MD5 is the digest of the package reported by the App Store.
+	MD5 string `json:"md5,omitempty"`
+	// Size is the size of the package, once the transfer started.
+	Size int64 `json:"size,omitempty"`
+}
+
+func newDownloadState(input DownloadInput, item downloadItemResult, externalVersionID string) downloadState {
+	if id, ok := item.Metadata["softwareVersionExternalIdentifier"]; ok && externalVersionID == "" {
+		externalVersionID = fmt.Sprint(id)
+	}
+
+	return downloadState{
+		AppID:             input.App.ID,
+		ExternalVersionID: externalVersionID,
+		MD5:               item.HashMD5,
+	}
+}
+
+func downloadStatePath(path string) string {
+	return path + ".state"
+}
+
+// matches returns whether both states describe the same package.
+func (s downloadState) matches(other downloadState) bool {
+	return s.AppID == other.AppID &&
+		s.ExternalVersionID == other.ExternalVersionID &&
+		strings.EqualFold(s.MD5, other.MD5)
+}
+
+// preparePartialDownload discards the partial download at the path unless its state matches the
+// expected state and returns the state of the download.
+func (t *appstore) preparePartialDownload(path string, expected downloadState) (downloadState, error) {
+	info, err := t.os.Stat(path)
+	if err != nil && !os.IsNotExist(err) {
+		return downloadState{}, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	if err != nil || info == nil {
+		return expected, nil
+	}
+
+	// Partial downloads without a state, e.g. left by older versions, cannot be verified.
+	state, err := t.readDownloadState(downloadStatePath(path))
+	if err == nil && state.matches(expected) && (state.Size == 0 || info.Size() <= state.Size) {
+		return state, nil
+	}
+
+	err = t.discardPartialDownload(path)
+	if err != nil {
+		return downloadState{}, err
+	}
+
+	return expected, nil
+}
+
+// verifyPartialDownload verifies the digest of the complete download, discarding it when it does not
+// match the digest reported by the App Store.
+func (t *appstore) verifyPartialDownload(path string, state downloadState) error {
+	if state.MD5 == "" {
+		return nil
+	}
+
+	file, err := t.os.OpenFile(path, os.O_RDONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+
+	hash := md5.New()
+	_, err = io.Copy(hash, file)
+
+	file.Close()
+
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if strings.EqualFold(hex.EncodeToString(hash.Sum(nil)), state.MD5) {
+		return nil
+	}
+
+	err = t.discardPartialDownload(path)
+	if err != nil {
+		return err
+	}
+
+	return errors.New("digest of the downloaded file does not match the digest reported by the App Store")
+}
+
+// discardPartialDownload removes the partial download at the path along with its state.
+func (t *appstore) discardPartialDownload(path string) error {
+	for _, name := range []string{path, downloadStatePath(path)} {
+		err := t.os.Remove(name)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (t *appstore) readDownloadState(path string) (downloadState, error) {
+	file, err := t.os.OpenFile(path, os.O_RDONLY, 0)
+	if err != nil {
+		return downloadState{}, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	var state downloadState
+
+	err = json.NewDecoder(file).Decode(&state)
+	if err != nil {
+		return downloadState{}, fmt.Errorf("failed to decode download state: %w", err)
+	}
+
+	return state, nil
+}
+
+func (t *appstore) writeDownloadState(path string, state downloadState) error {
+	file, err := t.os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+
+	err = json.NewEncoder(file).Encode(state)
+	closeErr := file.Close()
+
+	if err != nil {
+		return fmt.Errorf("failed to encode download state: %w", err)
+	}
+
+	if closeErr != nil {
+		return fmt.Errorf("failed to close file: %w", closeErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package appstore
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+
+	"github.com/majd/ipatool/v2/pkg/util/operatingsystem"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Download State", func() {
+	var (
+		as       *appstore
+		path     string
+		expected downloadState
+	)
+
+	BeforeEach(func() {
+		as = &appstore{os: operatingsystem.New()}
+		path = filepath.Join(GinkgoT().TempDir(), "app.ipa.tmp")
+		expected = downloadState{
+			AppID:             1234,
+			ExternalVersionID: "5678",
+			MD5:               md5Of("package"),
+		}
+	})
+
+	It("binds the partial download to the item", func() {
+		state := newDownloadState(DownloadInput{App: App{ID: 1234}}, downloadItemResult{
+			HashMD5:  expected.MD5,
+			Metadata: map[string]interface{}{"softwareVersionExternalIdentifier": 5678},
+		}, "")
+		Expect(state).To(Equal(expected))
+	})
+
+	When("there is no partial download", func() {
+		It("returns the expected state", func() {
+			state, err := as.preparePartialDownload(path, expected)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state).To(Equal(expected))
+		})
+	})
+
+	When("there is a partial download", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(path, []byte("pack"), 0600)).To(Succeed())
+		})
+
+		It("resumes the partial download of the same item", func() {
+			stored := expected
+			stored.Size = 7
+			Expect(as.writeDownloadState(downloadStatePath(path), stored)).To(Succeed())
+
+			state, err := as.preparePartialDownload(path, expected)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state).To(Equal(stored))
+			Expect(path).To(BeAnExistingFile())
+		})
+
+		DescribeTable("discards the mismatched partial download",
+			func(update func(state *downloadState)) {
+				stored := expected
+				update(&stored)
+				Expect(as.writeDownloadState(downloadStatePath(path), stored)).To(Succeed())
+
+				state, err := as.preparePartialDownload(path, expected)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(state).To(Equal(expected))
+				Expect(path).ToNot(BeAnExistingFile())
+				Expect(downloadStatePath(path)).ToNot(BeAnExistingFile())
+			},
+			Entry("other app", func(state *downloadState) { state.AppID = 1 }),
+			Entry("other version", func(state *downloadState) { state.ExternalVersionID = "1" }),
+			Entry("other digest", func(state *downloadState) { state.MD5 = "00" }),
+			Entry("larger than the package", func(state *downloadState) { state.Size = 2 }),
+		)
+
+		It("discards the partial download without state", func() {
+			_, err := as.preparePartialDownload(path, expected)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(path).ToNot(BeAnExistingFile())
+		})
+	})
+
+	When("verifying the download", func() {
+		It("accepts the download matching the digest", func() {
+			Expect(os.WriteFile(path, []byte("package"), 0600)).To(Succeed())
+			Expect(as.verifyPartialDownload(path, expected)).To(Succeed())
+		})
+
+		It("discards the download not matching the digest", func() {
+			Expect(os.WriteFile(path, []byte("corrupt"), 0600)).To(Succeed())
+			Expect(as.writeDownloadState(downloadStatePath(path), expected)).To(Succeed())
+
+			err := as.verifyPartialDownload(path, expected)
+			Expect(err).To(HaveOccurred())
+			Expect(path).ToNot(BeAnExistingFile())
+			Expect(downloadStatePath(path)).ToNot(BeAnExistingFile())
+		})
+	})
+})
+
+func md5Of(data string) string {
+	hash := md5.Sum([]byte(data))
+
+	return hex.EncodeToString(hash[:])
+}
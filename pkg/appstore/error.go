@@ -1,7 +1,17 @@
 package appstore
 
+import (
+	"github.com/majd/ipatool/v2/pkg/http"
+)
+
 type Error struct {
-	Metadata        interface{}
+	Metadata interface{}
+	// StatusCode is the HTTP status code of the response which caused the error, if any.
+	StatusCode int
+	// FailureType is the raw failure type reported by the App Store, if any.
+	FailureType string
+	// CustomerMessage is the raw customer message reported by the App Store, if any.
+	CustomerMessage string
 	underlyingError error
 }
 
@@ -9,9 +19,31 @@ func (t Error) Error() string {
 	return t.underlyingError.Error()
 }
 
+func (t Error) Unwrap() error {
+	return t.underlyingError
+}
+
 func NewErrorWithMetadata(err error, metadata interface{}) *Error {
 	return &Error{
 		underlyingError: err,
 		Metadata:        metadata,
 	}
 }
+
+// failureResponse is implemented by App Store responses which report failures.
+type failureResponse interface {
+	failure() (failureType string, customerMessage string)
+}
+
+// newResponseError returns an error carrying the response as metadata, along with its status code and
+// the failure reported by the App Store.
+func newResponseError[R interface{}](err error, res http.Result[R]) *Error {
+	result := NewErrorWithMetadata(err, res)
+	result.StatusCode = res.StatusCode
+
+	if response, ok := interface{}(res.Data).(failureResponse); ok {
+		result.FailureType, result.CustomerMessage = response.failure()
+	}
+
+	return result
+}
@@ -0,0 +1,85 @@
+package appstore
+
+import (
+	"errors"
+	gohttp "net/http"
+)
+
+// ErrorCode is a stable, machine-readable identifier of a class of errors.
+type ErrorCode string
+
+const (
+	ErrorCodeUnknown                  ErrorCode = "unknown"
+	ErrorCodeAppStore                 ErrorCode = "app_store_error"
+	ErrorCodeLicenseRequired          ErrorCode = "license_required"
+	ErrorCodeLicenseAlreadyExists     ErrorCode = "license_already_exists"
+	ErrorCodePasswordTokenExpired     ErrorCode = "password_token_expired"
+	ErrorCodeAuthCodeRequired         ErrorCode = "auth_code_required"
+	ErrorCodeInvalidCredentials       ErrorCode = "invalid_credentials"
+	ErrorCodeAccountDisabled          ErrorCode = "account_disabled"
+	ErrorCodeSubscriptionRequired     ErrorCode = "subscription_required"
+	ErrorCodeTemporarilyUnavailable   ErrorCode = "temporarily_unavailable"
+	ErrorCodeAppNotFound              ErrorCode = "app_not_found"
+	ErrorCodeDeveloperNotFound        ErrorCode = "developer_not_found"
+	ErrorCodeRateLimited              ErrorCode = "rate_limited"
+	ErrorCodeDeviceVerificationFailed ErrorCode = "device_verification_failed"
+	ErrorCodePackageExists            ErrorCode = "package_exists"
+	ErrorCodeInsufficientDiskSpace    ErrorCode = "insufficient_disk_space"
+	ErrorCodeNotAuthenticated         ErrorCode = "not_authenticated"
+)
+
+var sentinelErrorCodes = []struct {
+	err  error
+	code ErrorCode
+}{
+	{err: ErrLicenseRequired, code: ErrorCodeLicenseRequired},
+	{err: ErrLicenseAlreadyExists, code: ErrorCodeLicenseAlreadyExists},
+	{err: ErrPasswordTokenExpired, code: ErrorCodePasswordTokenExpired},
+	{err: ErrAuthCodeRequired, code: ErrorCodeAuthCodeRequired},
+	{err: ErrSubscriptionRequired, code: ErrorCodeSubscriptionRequired},
+	{err: ErrTemporarilyUnavailable, code: ErrorCodeTemporarilyUnavailable},
+	{err: ErrAppNotFound, code: ErrorCodeAppNotFound},
+	{err: ErrDeveloperNotFound, code: ErrorCodeDeveloperNotFound},
+	{err: ErrPackageExists, code: ErrorCodePackageExists},
+	{err: ErrInsufficientDiskSpace, code: ErrorCodeInsufficientDiskSpace},
+	{err: ErrNotAuthenticated, code: ErrorCodeNotAuthenticated},
+}
+
+// codedError is implemented by the errors of other packages which report their own code.
+type codedError interface {
+	error
+	ErrorCode() string
+}
+
+// ErrorCodeOf returns the code of the specified error. Errors not originating from the App Store are
+// reported as unknown, unless they report their own code.
+func ErrorCodeOf(err error) ErrorCode {
+	for _, sentinel := range sentinelErrorCodes {
+		if errors.Is(err, sentinel.err) {
+			return sentinel.code
+		}
+	}
+
+	var coded codedError
+	if errors.As(err, &coded) {
+		return ErrorCode(coded.ErrorCode())
+	}
+
+	var appstoreErr *Error
+	if !errors.As(err, &appstoreErr) {
+		return ErrorCodeUnknown
+	}
+
+	switch {
+	case appstoreErr.StatusCode == gohttp.StatusTooManyRequests:
+		return ErrorCodeRateLimited
+	case appstoreErr.FailureType == FailureTypeInvalidCredentials:
+		return ErrorCodeInvalidCredentials
+	case appstoreErr.FailureType == FailureTypeDeviceVerificationFailed:
+		return ErrorCodeDeviceVerificationFailed
+	case appstoreErr.CustomerMessage == CustomerMessageAccountDisabled:
+		return ErrorCodeAccountDisabled
+	default:
+		return ErrorCodeAppStore
+	}
+}
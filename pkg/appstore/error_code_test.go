@@ -0,0 +1,56 @@
+package appstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/majd/ipatool/v2/pkg/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ErrorCode", func() {
+	DescribeTable("maps errors to codes",
+		func(err error, expected ErrorCode) {
+			Expect(ErrorCodeOf(err)).To(Equal(expected))
+		},
+		Entry("unknown error", errors.New("error"), ErrorCodeUnknown),
+		Entry("license required", fmt.Errorf("wrapped: %w", ErrLicenseRequired), ErrorCodeLicenseRequired),
+		Entry("license already exists", ErrLicenseAlreadyExists, ErrorCodeLicenseAlreadyExists),
+		Entry("password token expired", ErrPasswordTokenExpired, ErrorCodePasswordTokenExpired),
+		Entry("auth code required", ErrAuthCodeRequired, ErrorCodeAuthCodeRequired),
+		Entry("subscription required", ErrSubscriptionRequired, ErrorCodeSubscriptionRequired),
+		Entry("temporarily unavailable", ErrTemporarilyUnavailable, ErrorCodeTemporarilyUnavailable),
+		Entry("app not found", ErrAppNotFound, ErrorCodeAppNotFound),
+		Entry("developer not found", ErrDeveloperNotFound, ErrorCodeDeveloperNotFound),
+		Entry("package exists", fmt.Errorf("%w: app.ipa", ErrPackageExists), ErrorCodePackageExists),
+		Entry("insufficient disk space", fmt.Errorf("%w: 2 bytes are required", ErrInsufficientDiskSpace), ErrorCodeInsufficientDiskSpace),
+		Entry("not authenticated", ErrNotAuthenticated, ErrorCodeNotAuthenticated),
+		Entry("error reporting its own code", fmt.Errorf("wrapped: %w", testCodedError{}), ErrorCode("custom_code")),
+		Entry("rate limited",
+			newResponseError(errors.New("error"), http.Result[searchResult]{StatusCode: 429}),
+			ErrorCodeRateLimited),
+		Entry("invalid credentials",
+			newResponseError(errors.New("error"), http.Result[loginResult]{Data: loginResult{FailureType: FailureTypeInvalidCredentials}}),
+			ErrorCodeInvalidCredentials),
+		Entry("device verification failed",
+			newResponseError(errors.New("error"), http.Result[downloadResult]{Data: downloadResult{FailureType: FailureTypeDeviceVerificationFailed}}),
+			ErrorCodeDeviceVerificationFailed),
+		Entry("account disabled",
+			newResponseError(errors.New("error"), http.Result[loginResult]{Data: loginResult{CustomerMessage: CustomerMessageAccountDisabled}}),
+			ErrorCodeAccountDisabled),
+		Entry("other App Store failure",
+			fmt.Errorf("wrapped: %w", newResponseError(errors.New("error"), http.Result[purchaseResult]{Data: purchaseResult{FailureType: "1"}})),
+			ErrorCodeAppStore),
+	)
+})
+
+type testCodedError struct{}
+
+func (testCodedError) Error() string {
+	return "coded error"
+}
+
+func (testCodedError) ErrorCode() string {
+	return "custom_code"
+}
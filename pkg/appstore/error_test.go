@@ -0,0 +1,44 @@
+package appstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/majd/ipatool/v2/pkg/http"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Error", func() {
+	It("unwraps the underlying error", func() {
+		err := fmt.Errorf("wrapped: %w", NewErrorWithMetadata(ErrAppNotFound, nil))
+		Expect(err).To(MatchError(ErrAppNotFound))
+	})
+
+	When("the response reports a failure", func() {
+		It("captures the status code and the failure", func() {
+			err := newResponseError(errors.New("failed"), http.Result[purchaseResult]{
+				StatusCode: 200,
+				Data: purchaseResult{
+					FailureType:     "1234",
+					CustomerMessage: "message",
+				},
+			})
+
+			Expect(err.StatusCode).To(Equal(200))
+			Expect(err.FailureType).To(Equal("1234"))
+			Expect(err.CustomerMessage).To(Equal("message"))
+			Expect(err.Metadata).To(BeAssignableToTypeOf(http.Result[purchaseResult]{}))
+		})
+	})
+
+	When("the response does not report failures", func() {
+		It("captures the status code", func() {
+			err := newResponseError(errors.New("failed"), http.Result[searchResult]{StatusCode: 503})
+
+			Expect(err.StatusCode).To(Equal(503))
+			Expect(err.FailureType).To(BeEmpty())
+			Expect(err.CustomerMessage).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,86 @@
+package appstore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrPackageExists = errors.New("app package already exists")
+
+// ExistsPolicy determines what happens when the destination of a download already exists.
+type ExistsPolicy string
+
+const (
+	// ExistsPolicyOverwrite replaces the existing file. It is the default policy.
+	ExistsPolicyOverwrite ExistsPolicy = "overwrite"
+	// ExistsPolicySkip keeps the existing file and skips the download.
+	ExistsPolicySkip ExistsPolicy = "skip"
+	// ExistsPolicyRename downloads the package next to the existing file, e.g. to "app (1).ipa".
+	ExistsPolicyRename ExistsPolicy = "rename"
+	// ExistsPolicyFail fails the download with ErrPackageExists.
+	ExistsPolicyFail ExistsPolicy = "fail"
+)
+
+func ParseExistsPolicy(value string) (ExistsPolicy, error) {
+	switch policy := ExistsPolicy(value); policy {
+	case "":
+		return ExistsPolicyOverwrite, nil
+	case ExistsPolicyOverwrite, ExistsPolicySkip, ExistsPolicyRename, ExistsPolicyFail:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid policy %q; supported policies are skip, overwrite, rename and fail", value)
+	}
+}
+
+// applyExistsPolicy returns the path the package is downloaded to and whether the download is skipped.
+func (t *appstore) applyExistsPolicy(path string, policy ExistsPolicy) (string, bool, error) {
+	if policy == "" || policy == ExistsPolicyOverwrite {
+		return path, false, nil
+	}
+
+	exists, err := t.fileExists(path)
+	if err != nil {
+		return "", false, err
+	}
+
+	if !exists {
+		return path, false, nil
+	}
+
+	switch policy {
+	case ExistsPolicySkip:
+		return path, true, nil
+	case ExistsPolicyFail:
+		return "", false, fmt.Errorf("%w: %s", ErrPackageExists, path)
+	case ExistsPolicyRename:
+		ext := filepath.Ext(path)
+		base := strings.TrimSuffix(path, ext)
+
+		for i := 1; ; i++ {
+			candidate := fmt.Sprintf("%s (%d)%s", base, i, ext)
+
+			exists, err := t.fileExists(candidate)
+			if err != nil {
+				return "", false, err
+			}
+
+			if !exists {
+				return candidate, false, nil
+			}
+		}
+	default:
+		return "", false, fmt.Errorf("invalid policy %q", policy)
+	}
+}
+
+func (t *appstore) fileExists(path string) (bool, error) {
+	info, err := t.os.Stat(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read file metadata: %w", err)
+	}
+
+	return err == nil && info != nil, nil
+}
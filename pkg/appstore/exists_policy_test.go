@@ -0,0 +1,94 @@
+package appstore
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/majd/ipatool/v2/pkg/util/operatingsystem"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Exists Policy", func() {
+	DescribeTable("parses policies",
+		func(value string, expected ExistsPolicy) {
+			policy, err := ParseExistsPolicy(value)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policy).To(Equal(expected))
+		},
+		Entry("default", "", ExistsPolicyOverwrite),
+		Entry("skip", "skip", ExistsPolicySkip),
+		Entry("overwrite", "overwrite", ExistsPolicyOverwrite),
+		Entry("rename", "rename", ExistsPolicyRename),
+		Entry("fail", "fail", ExistsPolicyFail),
+	)
+
+	It("rejects unknown policies", func() {
+		_, err := ParseExistsPolicy("keep")
+		Expect(err).To(HaveOccurred())
+	})
+
+	When("applying the policy", func() {
+		var (
+			as   *appstore
+			dir  string
+			path string
+		)
+
+		BeforeEach(func() {
+			as = &appstore{os: operatingsystem.New()}
+			dir = GinkgoT().TempDir()
+			path = filepath.Join(dir, "app.ipa")
+		})
+
+		When("the destination does not exist", func() {
+			DescribeTable("downloads to the destination",
+				func(policy ExistsPolicy) {
+					result, skip, err := as.applyExistsPolicy(path, policy)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(skip).To(BeFalse())
+					Expect(result).To(Equal(path))
+				},
+				Entry("skip", ExistsPolicySkip),
+				Entry("overwrite", ExistsPolicyOverwrite),
+				Entry("rename", ExistsPolicyRename),
+				Entry("fail", ExistsPolicyFail),
+			)
+		})
+
+		When("the destination exists", func() {
+			BeforeEach(func() {
+				Expect(os.WriteFile(path, []byte("package"), 0600)).To(Succeed())
+			})
+
+			It("skips the download", func() {
+				result, skip, err := as.applyExistsPolicy(path, ExistsPolicySkip)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(skip).To(BeTrue())
+				Expect(result).To(Equal(path))
+			})
+
+			It("overwrites the destination", func() {
+				result, skip, err := as.applyExistsPolicy(path, ExistsPolicyOverwrite)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(skip).To(BeFalse())
+				Expect(result).To(Equal(path))
+			})
+
+			It("renames the destination", func() {
+				Expect(os.WriteFile(filepath.Join(dir, "app (1).ipa"), []byte("package"), 0600)).To(Succeed())
+
+				result, skip, err := as.applyExistsPolicy(path, ExistsPolicyRename)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(skip).To(BeFalse())
+				Expect(result).To(Equal(filepath.Join(dir, "app (2).ipa")))
+			})
+
+			It("returns error", func() {
+				_, _, err := as.applyExistsPolicy(path, ExistsPolicyFail)
+				Expect(err).To(MatchError(ErrPackageExists))
+				Expect(ErrorCodeOf(err)).To(Equal(ErrorCodePackageExists))
+			})
+		})
+	})
+})
@@ -0,0 +1,136 @@
+package appstore
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// FileNameData is the data the file name templates of downloads are rendered with, e.g.
+// `{{.BundleID}}/{{.Version}}/{{.Name}}-{{.ExternalVersionID}}.ipa`.
+type FileNameData struct {
+	// App is the app being downloaded, as specified by the caller, e.g. `{{.App.ArtistName}}`.
+	App               App
+	AppID             int64
+	BundleID          string
+	Name              string
+	Version           string
+	ExternalVersionID string
+	Platform          string
+	// Country is the country code of the store front of the account.
+	Country string
+	// Date is the time of the download, e.g. `{{.Date.Format "2006-01-02"}}`.
+	Date time.Time
+	// Metadata holds the iTunes metadata of the item, e.g. `{{.Metadata.bundleVersion}}` or
+	// `{{.Metadata.releaseDate}}`. Missing keys render as empty strings.
+	Metadata map[string]string
+}
+
+// ParseFileNameTemplate parses a Go template rendering the path of downloaded app packages relative to
+// the output directory. The template is validated against empty data, so that unknown fields are
+// reported before downloading.
+func ParseFileNameTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("file-name").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file name template: %w", err)
+	}
+
+	err = tmpl.Execute(&strings.Builder{}, FileNameData{Metadata: map[string]string{}})
+	if err != nil {
+		return nil, fmt.Errorf("invalid file name template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// renderFileName renders the template and sanitizes the resulting path, so that it stays within the
+// output directory and only contains characters which are safe on every file system.
+func renderFileName(tmpl *template.Template, data FileNameData) (string, error) {
+	var buf strings.Builder
+
+	err := tmpl.Execute(&buf, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to render file name template: %w", err)
+	}
+
+	var segments []string
+
+	for _, segment := range strings.Split(strings.ReplaceAll(buf.String(), "\\", "/"), "/") {
+		segment = sanitizePathSegment(segment)
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	if len(segments) == 0 {
+		return "", errors.New("file name template rendered an empty path")
+	}
+
+	path := filepath.Join(segments...)
+	if !strings.EqualFold(filepath.Ext(path), ".ipa") {
+		path += ".ipa"
+	}
+
+	return path, nil
+}
+
+// sanitizePathSegment replaces the characters which are reserved on common file systems and removes
+// the leading and trailing spaces and dots, which also turns relative segments such as ".." into empty ones.
+func sanitizePathSegment(segment string) string {
+	segment = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(`<>:"|?*`, r) {
+			return '_'
+		}
+
+		return r
+	}, segment)
+
+	return strings.Trim(segment, " .")
+}
+
+// fileNameData returns the data the file name template of the download is rendered with.
+func fileNameData(input DownloadInput, item downloadItemResult, version string, externalVersionID string) FileNameData {
+	metadata := make(map[string]string, len(item.Metadata))
+
+	for key, value := range item.Metadata {
+		if date, ok := value.(time.Time); ok {
+			metadata[key] = date.UTC().Format(time.RFC3339)
+
+			continue
+		}
+
+		metadata[key] = fmt.Sprint(value)
+	}
+
+	if externalVersionID == "" {
+		externalVersionID = metadata["softwareVersionExternalIdentifier"]
+	}
+
+	country, _ := countryCodeFromStoreFront(input.Account.StoreFront)
+
+	return FileNameData{
+		App:               input.App,
+		AppID:             input.App.ID,
+		BundleID:          firstNonEmpty(input.App.BundleID, metadata["softwareVersionBundleId"]),
+		Name:              firstNonEmpty(input.App.Name, metadata["itemName"]),
+		Version:           version,
+		ExternalVersionID: externalVersionID,
+		Platform:          string(input.Platform),
+		Country:           country,
+		Date:              time.Now(),
+		Metadata:          metadata,
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,130 @@
+package appstore
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+
+	"github.com/majd/ipatool/v2/pkg/util/operatingsystem"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("File Name", func() {
+	data := FileNameData{
+		AppID:             1234,
+		BundleID:          "app.bundle.id",
+		Name:              "My: App?",
+		Version:           "1.2.3",
+		ExternalVersionID: "5678",
+		Platform:          "iphone",
+		Country:           "US",
+		Date:              time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Metadata:          map[string]string{"bundleVersion": "42"},
+	}
+
+	DescribeTable("renders the template",
+		func(text string, expected string) {
+			tmpl, err := ParseFileNameTemplate(text)
+			Expect(err).ToNot(HaveOccurred())
+
+			path, err := renderFileName(tmpl, data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(path).To(Equal(filepath.FromSlash(expected)))
+		},
+		Entry("nested directories", "{{.BundleID}}/{{.Version}}/{{.Name}}-{{.ExternalVersionID}}-{{.Platform}}.ipa", "app.bundle.id/1.2.3/My_ App_-5678-iphone.ipa"),
+		Entry("item metadata and date", `{{.Country}}/{{.Date.Format "2006-01-02"}}/{{.Metadata.bundleVersion}}`, "US/2024-05-01/42.ipa"),
+		Entry("missing metadata", "{{.BundleID}}{{.Metadata.missing}}.ipa", "app.bundle.id.ipa"),
+		Entry("parent directories", "../../{{.BundleID}}/./..//x.ipa", "app.bundle.id/x.ipa"),
+		Entry("absolute path", "/etc/{{.AppID}}.IPA", "etc/1234.IPA"),
+	)
+
+	DescribeTable("rejects invalid templates",
+		func(text string) {
+			_, err := ParseFileNameTemplate(text)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("syntax error", "{{.BundleID"),
+		Entry("unknown field", "{{.Unknown}}.ipa"),
+	)
+
+	When("the template renders an empty path", func() {
+		It("returns error", func() {
+			tmpl, err := ParseFileNameTemplate("{{.Metadata.missing}}/..")
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = renderFileName(tmpl, data)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("rendering the destination path", func() {
+		var (
+			ctrl   *gomock.Controller
+			mockOS *operatingsystem.MockOperatingSystem
+			as     *appstore
+		)
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			mockOS = operatingsystem.NewMockOperatingSystem(ctrl)
+			as = &appstore{os: mockOS}
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		It("creates the directories of the path", func() {
+			tmpl, err := ParseFileNameTemplate("{{.BundleID}}/{{.Version}}.ipa")
+			Expect(err).ToNot(HaveOccurred())
+
+			mockOS.EXPECT().
+				Getwd().
+				Return("/work", nil)
+
+			mockOS.EXPECT().
+				MkdirAll(filepath.Join("/work", "app.bundle.id"), gomock.Any()).
+				Return(nil)
+
+			path, err := as.renderDestinationPath(data, tmpl, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(path).To(Equal(filepath.Join("/work", "app.bundle.id", "1.2.3.ipa")))
+		})
+
+		It("returns error when the directories cannot be created", func() {
+			tmpl, err := ParseFileNameTemplate("{{.BundleID}}/{{.Version}}.ipa")
+			Expect(err).ToNot(HaveOccurred())
+
+			mockOS.EXPECT().
+				MkdirAll(gomock.Any(), gomock.Any()).
+				Return(errors.New("permission denied"))
+
+			_, err = as.renderDestinationPath(data, tmpl, "/output")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("collecting the data of a download", func() {
+		It("falls back to the item metadata", func() {
+			result := fileNameData(DownloadInput{
+				App:      App{ID: 1234},
+				Account:  Account{StoreFront: "143441-1,29"},
+				Platform: PlatformIPad,
+			}, downloadItemResult{Metadata: map[string]interface{}{
+				"softwareVersionBundleId":           "app.bundle.id",
+				"itemName":                          "App",
+				"softwareVersionExternalIdentifier": 5678,
+				"releaseDate":                       time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			}}, "1.0", "")
+
+			Expect(result.BundleID).To(Equal("app.bundle.id"))
+			Expect(result.Name).To(Equal("App"))
+			Expect(result.ExternalVersionID).To(Equal("5678"))
+			Expect(result.Platform).To(Equal("ipad"))
+			Expect(result.Country).To(Equal("US"))
+			Expect(result.Metadata).To(HaveKeyWithValue("releaseDate", "2024-05-01T00:00:00Z"))
+		})
+	})
+})
@@ -0,0 +1,31 @@
+package appstore
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInsufficientDiskSpace = errors.New("insufficient disk space")
+
+// checkFreeSpace returns ErrInsufficientDiskSpace when fewer than the required bytes are available in the
+// directory. The check is skipped when the required size is unknown or the platform cannot report it.
+func (t *appstore) checkFreeSpace(dir string, required int64) error {
+	if required <= 0 {
+		return nil
+	}
+
+	available, err := t.os.FreeSpace(dir)
+	if errors.Is(err, errors.ErrUnsupported) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to check free space: %w", err)
+	}
+
+	if available < uint64(required) {
+		return fmt.Errorf("%w: %d bytes are required in %s, but only %d bytes are available", ErrInsufficientDiskSpace, required, dir, available)
+	}
+
+	return nil
+}
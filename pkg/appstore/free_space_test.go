@@ -0,0 +1,66 @@
+package appstore
+
+import (
+	"errors"
+
+	"github.com/majd/ipatool/v2/pkg/util/operatingsystem"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("Free Space", func() {
+	var (
+		ctrl   *gomock.Controller
+		mockOS *operatingsystem.MockOperatingSystem
+		as     *appstore
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockOS = operatingsystem.NewMockOperatingSystem(ctrl)
+		as = &appstore{os: mockOS}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("skips the check when the size is unknown", func() {
+		Expect(as.checkFreeSpace("/output", 0)).To(Succeed())
+	})
+
+	It("skips the check when the platform cannot report the free space", func() {
+		mockOS.EXPECT().
+			FreeSpace("/output").
+			Return(uint64(0), errors.ErrUnsupported)
+
+		Expect(as.checkFreeSpace("/output", 100)).To(Succeed())
+	})
+
+	It("succeeds when enough space is available", func() {
+		mockOS.EXPECT().
+			FreeSpace("/output").
+			Return(uint64(100), nil)
+
+		Expect(as.checkFreeSpace("/output", 100)).To(Succeed())
+	})
+
+	It("returns error when not enough space is available", func() {
+		mockOS.EXPECT().
+			FreeSpace("/output").
+			Return(uint64(99), nil)
+
+		err := as.checkFreeSpace("/output", 100)
+		Expect(err).To(MatchError(ErrInsufficientDiskSpace))
+		Expect(ErrorCodeOf(err)).To(Equal(ErrorCodeInsufficientDiskSpace))
+	})
+
+	It("returns error when the free space cannot be read", func() {
+		mockOS.EXPECT().
+			FreeSpace("/output").
+			Return(uint64(0), errors.New("failed"))
+
+		Expect(as.checkFreeSpace("/output", 100)).ToNot(Succeed())
+	})
+})
@@ -65,3 +65,11 @@ func (p Platform) metadataPlatform() (string, error) {
 		return "", fmt.Errorf("invalid platform %q", p)
 	}
 }
+
+func (p Platform) developerEntity() (string, error) {
+	if p == "" {
+		return "software,iPadSoftware,tvSoftware", nil
+	}
+
+	return p.lookupEntity()
+}
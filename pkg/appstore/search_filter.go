@@ -0,0 +1,147 @@
+package appstore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type SearchSort string
+
+const (
+	SearchSortRelevance   SearchSort = ""
+	SearchSortName        SearchSort = "name"
+	SearchSortPrice       SearchSort = "price"
+	SearchSortRating      SearchSort = "rating"
+	SearchSortRatingCount SearchSort = "rating-count"
+	SearchSortReleaseDate SearchSort = "release-date"
+)
+
+func ParseSearchSort(value string) (SearchSort, error) {
+	switch value {
+	case "", "relevance":
+		return SearchSortRelevance, nil
+	case "name":
+		return SearchSortName, nil
+	case "price":
+		return SearchSortPrice, nil
+	case "rating":
+		return SearchSortRating, nil
+	case "rating-count", "ratingCount":
+		return SearchSortRatingCount, nil
+	case "release-date", "releaseDate":
+		return SearchSortReleaseDate, nil
+	default:
+		return "", fmt.Errorf("invalid sort order %q", value)
+	}
+}
+
+// SearchFilter narrows down search results on the client side. Zero values disable the
+// corresponding criterion.
+type SearchFilter struct {
+	FreeOnly bool
+	MinPrice *float64
+	MaxPrice *float64
+	// MinRating is the minimum average user rating.
+	MinRating float64
+	// Genre matches any of the app's genres, ignoring case.
+	Genre string
+	// Seller matches part of the seller or artist name, ignoring case.
+	Seller string
+}
+
+func (f SearchFilter) matches(app App) bool {
+	if f.FreeOnly && app.Price != 0 {
+		return false
+	}
+
+	if f.MinPrice != nil && app.Price < *f.MinPrice {
+		return false
+	}
+
+	if f.MaxPrice != nil && app.Price > *f.MaxPrice {
+		return false
+	}
+
+	if app.AverageUserRating < f.MinRating {
+		return false
+	}
+
+	if f.Genre != "" && !matchesGenre(app, f.Genre) {
+		return false
+	}
+
+	if f.Seller != "" && !containsFold(app.SellerName, f.Seller) && !containsFold(app.ArtistName, f.Seller) {
+		return false
+	}
+
+	return true
+}
+
+func matchesGenre(app App, genre string) bool {
+	if strings.EqualFold(app.PrimaryGenre, genre) {
+		return true
+	}
+
+	for _, g := range app.Genres {
+		if strings.EqualFold(g, genre) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsFold(value, substr string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(substr))
+}
+
+// filterApps removes duplicated apps, which are returned once per matching entity, as well as the
+// apps not matching the filter.
+func filterApps(apps []App, filter SearchFilter) []App {
+	filtered := []App{}
+	seen := map[int64]bool{}
+
+	for _, app := range apps {
+		if seen[app.ID] || !filter.matches(app) {
+			continue
+		}
+
+		seen[app.ID] = true
+		filtered = append(filtered, app)
+	}
+
+	return filtered
+}
+
+// sortApps sorts the apps in place. Ties keep their relevance order.
+func sortApps(apps []App, order SearchSort, descending bool) {
+	if order == SearchSortRelevance {
+		return
+	}
+
+	less := func(a, b App) bool {
+		switch order {
+		case SearchSortName:
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		case SearchSortPrice:
+			return a.Price < b.Price
+		case SearchSortRating:
+			return a.AverageUserRating < b.AverageUserRating
+		case SearchSortRatingCount:
+			return a.UserRatingCount < b.UserRatingCount
+		case SearchSortReleaseDate:
+			return a.ReleaseDate.Before(b.ReleaseDate)
+		default:
+			return false
+		}
+	}
+
+	sort.SliceStable(apps, func(i, j int) bool {
+		if descending {
+			return less(apps[j], apps[i])
+		}
+
+		return less(apps[i], apps[j])
+	})
+}
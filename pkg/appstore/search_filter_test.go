@@ -0,0 +1,89 @@
+package appstore
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SearchFilter", func() {
+	DescribeTable("parses sort orders",
+		func(value string, expected SearchSort) {
+			order, err := ParseSearchSort(value)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(order).To(Equal(expected))
+		},
+		Entry("relevance", "", SearchSortRelevance),
+		Entry("name", "name", SearchSortName),
+		Entry("price", "price", SearchSortPrice),
+		Entry("rating", "rating", SearchSortRating),
+		Entry("rating count", "ratingCount", SearchSortRatingCount),
+		Entry("release date", "release-date", SearchSortReleaseDate),
+	)
+
+	It("rejects unknown sort orders", func() {
+		_, err := ParseSearchSort("size")
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("matches apps",
+		func(filter SearchFilter, expected bool) {
+			app := App{
+				Price:             2.99,
+				AverageUserRating: 4.2,
+				PrimaryGenre:      "Games",
+				Genres:            []string{"Games", "Puzzle"},
+				SellerName:        "Acme Inc.",
+				ArtistName:        "Acme",
+			}
+
+			Expect(filter.matches(app)).To(Equal(expected))
+		},
+		Entry("empty filter", SearchFilter{}, true),
+		Entry("free only", SearchFilter{FreeOnly: true}, false),
+		Entry("within price range", SearchFilter{MinPrice: ptr(1.0), MaxPrice: ptr(3.0)}, true),
+		Entry("below minimum price", SearchFilter{MinPrice: ptr(3.0)}, false),
+		Entry("above maximum price", SearchFilter{MaxPrice: ptr(1.0)}, false),
+		Entry("minimum rating met", SearchFilter{MinRating: 4}, true),
+		Entry("minimum rating not met", SearchFilter{MinRating: 4.5}, false),
+		Entry("secondary genre", SearchFilter{Genre: "puzzle"}, true),
+		Entry("other genre", SearchFilter{Genre: "Finance"}, false),
+		Entry("partial seller name", SearchFilter{Seller: "acme"}, true),
+		Entry("other seller", SearchFilter{Seller: "Globex"}, false),
+	)
+
+	It("removes duplicated apps", func() {
+		apps := filterApps([]App{{ID: 1}, {ID: 2}, {ID: 1}}, SearchFilter{})
+		Expect(apps).To(Equal([]App{{ID: 1}, {ID: 2}}))
+	})
+
+	DescribeTable("sorts apps",
+		func(order SearchSort, descending bool, expected []int64) {
+			apps := []App{
+				{ID: 1, Name: "b", Price: 1, UserRatingCount: 30, ReleaseDate: time.Unix(300, 0)},
+				{ID: 2, Name: "C", Price: 0, UserRatingCount: 10, ReleaseDate: time.Unix(100, 0)},
+				{ID: 3, Name: "a", Price: 1, UserRatingCount: 20, ReleaseDate: time.Unix(200, 0)},
+			}
+
+			sortApps(apps, order, descending)
+
+			ids := make([]int64, len(apps))
+			for i, app := range apps {
+				ids[i] = app.ID
+			}
+
+			Expect(ids).To(Equal(expected))
+		},
+		Entry("relevance", SearchSortRelevance, false, []int64{1, 2, 3}),
+		Entry("name", SearchSortName, false, []int64{3, 1, 2}),
+		Entry("price keeps ties stable", SearchSortPrice, false, []int64{2, 1, 3}),
+		Entry("price descending keeps ties stable", SearchSortPrice, true, []int64{1, 3, 2}),
+		Entry("rating count descending", SearchSortRatingCount, true, []int64{1, 3, 2}),
+		Entry("release date", SearchSortReleaseDate, false, []int64{2, 3, 1}),
+	)
+})
+
+func ptr[T any](value T) *T {
+	return &value
+}
@@ -2,9 +2,77 @@ package appstore
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+
+	"github.com/majd/ipatool/v2/pkg/schema"
+	"github.com/rs/zerolog"
 )
 
+type StoreFront struct {
+	CountryCode string
+	ID          string
+}
+
+type StoreFrontList []StoreFront
+
+func (s StoreFront) MarshalZerologObject(event *zerolog.Event) {
+	event.
+		Str("countryCode", s.CountryCode).
+		Str("storeFrontID", s.ID)
+}
+
+func (list StoreFrontList) MarshalZerologArray(a *zerolog.Array) {
+	for _, storeFront := range list {
+		a.Object(storeFront)
+	}
+}
+
+func (list StoreFrontList) JSONSchema() *schema.Schema {
+	return schema.ArrayOf(&schema.Schema{
+		Type: "object",
+		Properties: map[string]*schema.Schema{
+			"countryCode":  schema.Of("string"),
+			"storeFrontID": schema.Of("string"),
+		},
+		Required: []string{"countryCode", "storeFrontID"},
+	})
+}
+
+// StoreFronts returns every supported store front, sorted by country code.
+func StoreFronts() StoreFrontList {
+	list := make(StoreFrontList, 0, len(storeFronts))
+	for countryCode, id := range storeFronts {
+		list = append(list, StoreFront{CountryCode: countryCode, ID: id})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CountryCode < list[j].CountryCode
+	})
+
+	return list
+}
+
+// ParseCountryCode validates the country code against the supported store fronts and returns it in its canonical form.
+func ParseCountryCode(value string) (string, error) {
+	countryCode := strings.ToUpper(strings.TrimSpace(value))
+
+	if _, ok := storeFronts[countryCode]; !ok {
+		return "", fmt.Errorf("country code (%s) is not supported", value)
+	}
+
+	return countryCode, nil
+}
+
+// resolveCountryCode returns the country to query, preferring an explicit override over the account's store front.
+func resolveCountryCode(country, storeFront string) (string, error) {
+	if country != "" {
+		return ParseCountryCode(country)
+	}
+
+	return countryCodeFromStoreFront(storeFront)
+}
+
 func countryCodeFromStoreFront(storeFront string) (string, error) {
 	for key, val := range storeFronts {
 		parts := strings.Split(storeFront, "-")
@@ -0,0 +1,59 @@
+package appstore
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StoreFront", func() {
+	When("listing store fronts", func() {
+		It("returns every store front sorted by country code", func() {
+			list := StoreFronts()
+			Expect(list).To(HaveLen(len(storeFronts)))
+			Expect(list[0].CountryCode).To(Equal("AE"))
+			Expect(list).To(ContainElement(StoreFront{CountryCode: "US", ID: "143441"}))
+
+			for i := 1; i < len(list); i++ {
+				Expect(list[i-1].CountryCode < list[i].CountryCode).To(BeTrue())
+			}
+		})
+	})
+
+	DescribeTable("parses country codes",
+		func(value, expected string, valid bool) {
+			countryCode, err := ParseCountryCode(value)
+			if !valid {
+				Expect(err).To(HaveOccurred())
+
+				return
+			}
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(countryCode).To(Equal(expected))
+		},
+		Entry("upper case", "DE", "DE", true),
+		Entry("lower case", "de", "DE", true),
+		Entry("surrounding whitespace", " us ", "US", true),
+		Entry("unsupported country", "XX", "", false),
+		Entry("empty", "", "", false),
+	)
+
+	When("resolving the country code", func() {
+		It("prefers the override", func() {
+			countryCode, err := resolveCountryCode("de", "143441-1,29")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(countryCode).To(Equal("DE"))
+		})
+
+		It("falls back to the store front", func() {
+			countryCode, err := resolveCountryCode("", "143441-1,29")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(countryCode).To(Equal("US"))
+		})
+
+		It("returns error for unsupported override", func() {
+			_, err := resolveCountryCode("XX", "143441-1,29")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
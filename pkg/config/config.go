@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Config is a set of settings persisted in a YAML file. Nested mappings are addressed with dotted
+// keys, e.g. "download.output" for the output key of the download mapping.
+//
+//go:generate go run go.uber.org/mock/mockgen -source=config.go -destination=config_mock.go -package config
+type Config interface {
+	// Path returns the path of the configuration file.
+	Path() string
+	// Get returns the value of the specified key. The items of a sequence are joined with commas.
+	Get(key string) (string, bool)
+	// GetList returns the items of the sequence of the specified key, or the value of the key as a single
+	// item when it is not a sequence.
+	GetList(key string) ([]string, bool)
+	// Set sets and persists the value of the specified key.
+	Set(key, value string) error
+	// Unset removes and persists the removal of the specified key.
+	Unset(key string) error
+	// Keys returns the keys of all the values, sorted alphabetically.
+	Keys() []string
+}
+
+type config struct {
+	path   string
+	values map[string]string
+	// lists holds the items of the values which are sequences.
+	lists map[string][]string
+}
+
+type Args struct {
+	Path string
+}
+
+// New loads the configuration from the specified file. A missing file is treated as an empty configuration.
+func New(args Args) (Config, error) {
+	values, lists, err := load(args.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &config{
+		path:   args.Path,
+		values: values,
+		lists:  lists,
+	}, nil
+}
+
+func (c *config) Path() string {
+	return c.path
+}
+
+func (c *config) Get(key string) (string, bool) {
+	value, ok := c.values[key]
+
+	return value, ok
+}
+
+func (c *config) GetList(key string) ([]string, bool) {
+	if items, ok := c.lists[key]; ok {
+		return append([]string{}, items...), true
+	}
+
+	value, ok := c.values[key]
+	if !ok {
+		return nil, false
+	}
+
+	return []string{value}, true
+}
+
+func (c *config) Set(key, value string) error {
+	if key == "" {
+		return fmt.Errorf("key must not be empty")
+	}
+
+	values, lists := c.copyValues()
+	values[key] = value
+	delete(lists, key)
+
+	return c.save(values, lists)
+}
+
+func (c *config) Unset(key string) error {
+	values, lists := c.copyValues()
+	delete(values, key)
+	delete(lists, key)
+
+	return c.save(values, lists)
+}
+
+func (c *config) Keys() []string {
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (c *config) copyValues() (map[string]string, map[string][]string) {
+	values := make(map[string]string, len(c.values))
+	for key, value := range c.values {
+		values[key] = value
+	}
+
+	lists := make(map[string][]string, len(c.lists))
+	for key, items := range c.lists {
+		lists[key] = items
+	}
+
+	return values, lists
+}
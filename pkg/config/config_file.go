@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/majd/ipatool/v2/pkg/util"
+	"gopkg.in/yaml.v3"
+)
+
+func load(path string) (map[string]string, map[string][]string, error) {
+	values := map[string]string{}
+	lists := map[string][]string{}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return values, lists, nil
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var document map[string]interface{}
+
+	err = yaml.Unmarshal(data, &document)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	flatten("", document, values, lists)
+
+	return values, lists, nil
+}
+
+// flatten converts nested mappings into dotted keys. Sequences are joined with commas, which is the
+// syntax accepted by flags taking multiple values, and their items are kept in lists for the flags
+// taking each item separately.
+func flatten(prefix string, document map[string]interface{}, values map[string]string, lists map[string][]string) {
+	for key, value := range document {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flatten(key, v, values, lists)
+		case []interface{}:
+			parts := make([]string, len(v))
+			for i, item := range v {
+				parts[i] = fmt.Sprint(item)
+			}
+
+			values[key] = strings.Join(parts, ",")
+			lists[key] = parts
+		case nil:
+			values[key] = ""
+		default:
+			values[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+func (c *config) save(values map[string]string, lists map[string][]string) error {
+	document := map[string]interface{}{}
+
+	for key, value := range values {
+		var item interface{} = value
+		if items, ok := lists[key]; ok {
+			item = items
+		}
+
+		err := insert(document, strings.Split(key, "."), item)
+		if err != nil {
+			return fmt.Errorf("failed to set %q: %w", key, err)
+		}
+	}
+
+	data, err := yaml.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.path), 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	// Write atomically so that a failed write never corrupts the existing configuration.
+	err = util.WriteFileAtomic(c.path, data, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	c.values = values
+	c.lists = lists
+
+	return nil
+}
+
+func insert(document map[string]interface{}, path []string, value interface{}) error {
+	if len(path) == 1 {
+		if _, ok := document[path[0]].(map[string]interface{}); ok {
+			return fmt.Errorf("%q already holds nested values", path[0])
+		}
+
+		document[path[0]] = value
+
+		return nil
+	}
+
+	child, ok := document[path[0]].(map[string]interface{})
+	if !ok {
+		if _, exists := document[path[0]]; exists {
+			return fmt.Errorf("%q already holds a value", path[0])
+		}
+
+		child = map[string]interface{}{}
+		document[path[0]] = child
+	}
+
+	return insert(child, path[1:], value)
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "nested", "config.yaml")
+	})
+
+	When("the file does not exist", func() {
+		It("returns an empty configuration", func() {
+			cfg, err := New(Args{Path: path})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.Keys()).To(BeEmpty())
+			Expect(cfg.Path()).To(Equal(path))
+		})
+	})
+
+	When("the file is invalid", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+			Expect(os.WriteFile(path, []byte("format: [json"), 0600)).To(Succeed())
+		})
+
+		It("returns error", func() {
+			_, err := New(Args{Path: path})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("the file contains nested values", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+			Expect(os.WriteFile(path, []byte(""+
+				"format: json\n"+
+				"non-interactive: true\n"+
+				"country: [us, de]\n"+
+				"download:\n"+
+				"  output: /tmp/apps\n"+
+				"auth:\n"+
+				"  login:\n"+
+				"    email: user@example.com\n"), 0600)).To(Succeed())
+		})
+
+		It("flattens them into dotted keys", func() {
+			cfg, err := New(Args{Path: path})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.Keys()).To(Equal([]string{"auth.login.email", "country", "download.output", "format", "non-interactive"}))
+
+			value, ok := cfg.Get("non-interactive")
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("true"))
+
+			value, _ = cfg.Get("country")
+			Expect(value).To(Equal("us,de"))
+
+			value, _ = cfg.Get("auth.login.email")
+			Expect(value).To(Equal("user@example.com"))
+
+			_, ok = cfg.Get("download")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	When("the file contains a sequence of hooks", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+			Expect(os.WriteFile(path, []byte(""+
+				"post-download-hook:\n"+
+				"  - upload --dir a,b\n"+
+				"  - notify\n"), 0600)).To(Succeed())
+		})
+
+		It("returns each hook separately", func() {
+			cfg, err := New(Args{Path: path})
+			Expect(err).ToNot(HaveOccurred())
+
+			items, ok := cfg.GetList("post-download-hook")
+			Expect(ok).To(BeTrue())
+			Expect(items).To(Equal([]string{"upload --dir a,b", "notify"}))
+
+			value, _ := cfg.Get("post-download-hook")
+			Expect(value).To(Equal("upload --dir a,b,notify"))
+		})
+
+		It("keeps the sequence when setting another value", func() {
+			cfg, err := New(Args{Path: path})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.Set("format", "json")).To(Succeed())
+
+			cfg, err = New(Args{Path: path})
+			Expect(err).ToNot(HaveOccurred())
+
+			items, _ := cfg.GetList("post-download-hook")
+			Expect(items).To(Equal([]string{"upload --dir a,b", "notify"}))
+
+			items, ok := cfg.GetList("format")
+			Expect(ok).To(BeTrue())
+			Expect(items).To(Equal([]string{"json"}))
+		})
+	})
+
+	When("setting values", func() {
+		It("persists them", func() {
+			cfg, err := New(Args{Path: path})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(cfg.Set("format", "json")).To(Succeed())
+			Expect(cfg.Set("download.output", "/tmp/apps")).To(Succeed())
+
+			data, err := os.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(Equal("download:\n    output: /tmp/apps\nformat: json\n"))
+
+			info, err := os.Stat(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+			reloaded, err := New(Args{Path: path})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reloaded.Keys()).To(Equal([]string{"download.output", "format"}))
+		})
+
+		It("rejects keys conflicting with nested values", func() {
+			cfg, err := New(Args{Path: path})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(cfg.Set("download.output", "/tmp/apps")).To(Succeed())
+			Expect(cfg.Set("download", "value")).ToNot(Succeed())
+
+			_, ok := cfg.Get("download")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("rejects empty keys", func() {
+			cfg, err := New(Args{Path: path})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.Set("", "value")).ToNot(Succeed())
+		})
+	})
+
+	When("unsetting values", func() {
+		It("persists the removal", func() {
+			cfg, err := New(Args{Path: path})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(cfg.Set("format", "json")).To(Succeed())
+			Expect(cfg.Unset("format")).To(Succeed())
+
+			reloaded, err := New(Args{Path: path})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reloaded.Keys()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,38 @@
+package hook
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Checksums are the digests of an app package, in hexadecimal.
+type Checksums struct {
+	MD5    string `json:"md5"`
+	SHA256 string `json:"sha256"`
+}
+
+// ChecksumsOf returns the checksums of the file at the specified path.
+func ChecksumsOf(path string) (Checksums, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Checksums{}, fmt.Errorf("failed to open app package: %w", err)
+	}
+	defer file.Close()
+
+	md5Hash := md5.New()
+	sha256Hash := sha256.New()
+
+	_, err = io.Copy(io.MultiWriter(md5Hash, sha256Hash), file)
+	if err != nil {
+		return Checksums{}, fmt.Errorf("failed to hash app package: %w", err)
+	}
+
+	return Checksums{
+		MD5:    hex.EncodeToString(md5Hash.Sum(nil)),
+		SHA256: hex.EncodeToString(sha256Hash.Sum(nil)),
+	}, nil
+}
@@ -0,0 +1,28 @@
+package hook
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ChecksumsOf", func() {
+	It("returns the digests of the file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "app.ipa")
+		Expect(os.WriteFile(path, []byte("hello"), 0644)).To(Succeed())
+
+		checksums, err := ChecksumsOf(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(checksums).To(Equal(Checksums{
+			MD5:    "5d41402abc4b2a76b9719d911017c592",
+			SHA256: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		}))
+	})
+
+	It("returns an error when the file does not exist", func() {
+		_, err := ChecksumsOf(filepath.Join(GinkgoT().TempDir(), "missing.ipa"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,79 @@
+package hook
+
+import (
+	"context"
+	"io"
+)
+
+// ErrorCodeRejected is the stable code errors wrapping ErrRejected are reported with.
+const ErrorCodeRejected = "hook_rejected"
+
+// ErrRejected is returned when a hook exits with a non-zero status, vetoing the download.
+var ErrRejected error = &codedError{
+	code:    ErrorCodeRejected,
+	message: "post-download hook rejected the download",
+}
+
+// codedError is an error reporting its own stable code.
+type codedError struct {
+	code    string
+	message string
+}
+
+func (e *codedError) Error() string {
+	return e.message
+}
+
+// ErrorCode returns the stable code of the error.
+func (e *codedError) ErrorCode() string {
+	return e.code
+}
+
+// Event describes a downloaded app package. It is written as JSON to the standard input of the hooks.
+type Event struct {
+	Path              string    `json:"path"`
+	BundleID          string    `json:"bundleID,omitempty"`
+	Version           string    `json:"version,omitempty"`
+	ExternalVersionID string    `json:"externalVersionID,omitempty"`
+	Checksums         Checksums `json:"checksums"`
+	SinfCount         int       `json:"sinfCount"`
+	Account           string    `json:"account,omitempty"`
+}
+
+// Hooks runs commands after app packages are downloaded, e.g. to upload them or to send notifications.
+//
+//go:generate go run go.uber.org/mock/mockgen -source=hook.go -destination=hook_mock.go -package hook
+type Hooks interface {
+	// Run runs the commands in order with the event on their standard input. It stops at the first
+	// command which fails, returning ErrRejected when the command exits with a non-zero status.
+	Run(ctx context.Context, event Event) error
+	// Enabled returns whether any command is configured.
+	Enabled() bool
+}
+
+type hooks struct {
+	commands []string
+	output   io.Writer
+}
+
+type Args struct {
+	// Commands are run by the shell of the platform, i.e. `sh -c` or `cmd /C`.
+	Commands []string
+	// Output receives the standard output and the standard error of the commands. Defaults to discarding them.
+	Output io.Writer
+}
+
+func New(args Args) Hooks {
+	if args.Output == nil {
+		args.Output = io.Discard
+	}
+
+	return &hooks{
+		commands: args.Commands,
+		output:   args.Output,
+	}
+}
+
+func (h *hooks) Enabled() bool {
+	return len(h.commands) > 0
+}
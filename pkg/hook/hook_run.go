@@ -0,0 +1,62 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+func (h *hooks) Run(ctx context.Context, event Event) error {
+	if !h.Enabled() {
+		return nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hook event: %w", err)
+	}
+
+	for _, command := range h.commands {
+		err := h.run(ctx, command, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *hooks) run(ctx context.Context, command string, input []byte) error {
+	name, args := shellCommand(command)
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = h.output
+	cmd.Stderr = h.output
+
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && ctx.Err() == nil {
+		return fmt.Errorf("%w: %q exited with status %d", ErrRejected, command, exitErr.ExitCode())
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to run post-download hook %q: %w", command, err)
+	}
+
+	return nil
+}
+
+// shellCommand returns the name and the arguments of the shell invocation running the command.
+func shellCommand(command string) (string, []string) {
+	if runtime.GOOS == "windows" {
+		return "cmd", []string{"/C", command}
+	}
+
+	return "sh", []string{"-c", command}
+}
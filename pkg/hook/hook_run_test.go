@@ -0,0 +1,98 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hooks (Run)", func() {
+	var (
+		dir    string
+		output *bytes.Buffer
+		event  Event
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		output = new(bytes.Buffer)
+		event = Event{
+			Path:              "/apps/app.ipa",
+			BundleID:          "com.example.app",
+			Version:           "1.2.3",
+			ExternalVersionID: "123456",
+			Checksums:         Checksums{MD5: "md5", SHA256: "sha256"},
+			SinfCount:         1,
+			Account:           "test@example.com",
+		}
+	})
+
+	When("no command is configured", func() {
+		It("does nothing", func() {
+			hooks := New(Args{})
+
+			Expect(hooks.Enabled()).To(BeFalse())
+			Expect(hooks.Run(context.Background(), event)).To(Succeed())
+		})
+	})
+
+	When("the commands succeed", func() {
+		It("writes the event to the standard input of each command in order", func() {
+			path := filepath.Join(dir, "events")
+
+			hooks := New(Args{
+				Commands: []string{"cat >> " + path, "echo >> " + path, "echo done"},
+				Output:   output,
+			})
+
+			Expect(hooks.Enabled()).To(BeTrue())
+			Expect(hooks.Run(context.Background(), event)).To(Succeed())
+			Expect(output.String()).To(Equal("done\n"))
+
+			data, err := os.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+
+			var written Event
+			Expect(json.Unmarshal(data, &written)).To(Succeed())
+			Expect(written).To(Equal(event))
+		})
+	})
+
+	When("a command exits with a non-zero status", func() {
+		It("rejects the download and stops running commands", func() {
+			path := filepath.Join(dir, "ran")
+
+			hooks := New(Args{
+				Commands: []string{"echo rejected >&2; exit 3", "touch " + path},
+				Output:   output,
+			})
+
+			err := hooks.Run(context.Background(), event)
+			Expect(err).To(MatchError(ErrRejected))
+			Expect(err.Error()).To(ContainSubstring("exited with status 3"))
+
+			var coded interface{ ErrorCode() string }
+			Expect(errors.As(err, &coded)).To(BeTrue())
+			Expect(coded.ErrorCode()).To(Equal(ErrorCodeRejected))
+			Expect(output.String()).To(Equal("rejected\n"))
+			Expect(path).ToNot(BeAnExistingFile())
+		})
+	})
+
+	When("the context is cancelled", func() {
+		It("returns an error other than a rejection", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			err := New(Args{Commands: []string{"true"}}).Run(ctx, event)
+			Expect(err).To(HaveOccurred())
+			Expect(err).ToNot(MatchError(ErrRejected))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package hook
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestHook(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Hook Suite")
+}
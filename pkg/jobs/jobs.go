@@ -0,0 +1,140 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrJobNotFound   = errors.New("job not found")
+	ErrInvalidState  = errors.New("operation is not allowed in the current state of the job")
+	ErrRunnerActive  = errors.New("the jobs are already being run by another process")
+	defaultPollDelay = time.Second
+)
+
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Request describes the app package a job downloads.
+type Request struct {
+	AppID             int64  `json:"appID,omitempty"`
+	BundleID          string `json:"bundleID,omitempty"`
+	ExternalVersionID string `json:"externalVersionID,omitempty"`
+	Platform          string `json:"platform,omitempty"`
+	Purchase          bool   `json:"purchase,omitempty"`
+	OutputPath        string `json:"outputPath,omitempty"`
+	// NameTemplate is the template of the path of the package within the output path.
+	NameTemplate string `json:"nameTemplate,omitempty"`
+	// IfExists is the policy applied when the package already exists, e.g. "skip".
+	IfExists string `json:"ifExists,omitempty"`
+	// Account is the email of the account the job was created with.
+	Account string `json:"account,omitempty"`
+}
+
+// Job is a persistent download of an app package.
+type Job struct {
+	ID            string    `json:"id"`
+	Status        Status    `json:"status" description:"one of queued, running, succeeded, failed or cancelled"`
+	Request       Request   `json:"request"`
+	Attempts      int       `json:"attempts"`
+	LastError     string    `json:"lastError,omitempty"`
+	LastErrorCode string    `json:"lastErrorCode,omitempty"`
+	Output        string    `json:"output,omitempty" description:"path of the downloaded app package"`
+	Purchased     bool      `json:"purchased,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	StartedAt     time.Time `json:"startedAt,omitempty"`
+	FinishedAt    time.Time `json:"finishedAt,omitempty"`
+}
+
+// Result describes the outcome of a successful job.
+type Result struct {
+	Output    string
+	Purchased bool
+}
+
+// Runner performs the download of a job. The context is cancelled when the job is cancelled or the
+// queue stops, in which case the download should stop as soon as possible.
+type Runner interface {
+	Run(ctx context.Context, job Job) (Result, error)
+}
+
+// Queue persists download jobs and runs them. The jobs can be managed from several processes at once,
+// but only one process at a time runs them.
+//
+//go:generate go run go.uber.org/mock/mockgen -source=jobs.go -destination=jobs_mock.go -package jobs
+type Queue interface {
+	// Enqueue adds a job downloading the requested app package.
+	Enqueue(request Request) (Job, error)
+	// Get returns the job with the specified ID.
+	Get(id string) (Job, error)
+	// List returns the jobs in the order they were created.
+	List() ([]Job, error)
+	// Cancel cancels a queued or running job.
+	Cancel(id string) (Job, error)
+	// Retry queues a failed or cancelled job again. A cancelled job can only be retried once its run stopped.
+	Retry(id string) (Job, error)
+	// Run runs the queued jobs until the context is cancelled.
+	Run(ctx context.Context) error
+	// RunPending runs the queued jobs until none is left.
+	RunPending(ctx context.Context) error
+}
+
+type queue struct {
+	path         string
+	runner       Runner
+	concurrency  int
+	pollInterval time.Duration
+	wake         chan struct{}
+}
+
+type Args struct {
+	// Path is the path of the file the jobs are stored in.
+	Path   string
+	Runner Runner
+	// Concurrency is the maximum number of jobs running at once. Defaults to 1.
+	Concurrency int
+	// PollInterval is how often changes made by other processes, e.g. cancellations, are picked up
+	// while running jobs. Defaults to one second.
+	PollInterval time.Duration
+}
+
+func New(args Args) Queue {
+	concurrency := args.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	pollInterval := args.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultPollDelay
+	}
+
+	return &queue{
+		path:         args.Path,
+		runner:       args.Runner,
+		concurrency:  concurrency,
+		pollInterval: pollInterval,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// notify wakes up the jobs being run by this process so that changes are picked up immediately.
+func (q *queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// now returns the current time truncated to seconds, the precision of the other timestamps in the output.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
@@ -0,0 +1,126 @@
+package jobs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+func (q *queue) Enqueue(request Request) (Job, error) {
+	id, err := newJobID()
+	if err != nil {
+		return Job{}, err
+	}
+
+	job := Job{
+		ID:        id,
+		Status:    StatusQueued,
+		Request:   request,
+		CreatedAt: now(),
+	}
+
+	err = q.update(func(jobs []Job) ([]Job, error) {
+		return append(jobs, job), nil
+	})
+	if err != nil {
+		return Job{}, err
+	}
+
+	q.notify()
+
+	return job, nil
+}
+
+func (q *queue) Get(id string) (Job, error) {
+	jobs, err := q.read()
+	if err != nil {
+		return Job{}, err
+	}
+
+	index, err := find(jobs, id)
+	if err != nil {
+		return Job{}, err
+	}
+
+	return jobs[index], nil
+}
+
+func (q *queue) List() ([]Job, error) {
+	return q.read()
+}
+
+func (q *queue) Cancel(id string) (Job, error) {
+	return q.transition(id, func(job *Job) error {
+		switch job.Status {
+		case StatusQueued:
+			job.FinishedAt = now()
+		case StatusRunning:
+			// The process running the job finishes it once the cancellation is picked up.
+		default:
+			return fmt.Errorf("%w: cannot cancel job %s, which is %s", ErrInvalidState, job.ID, job.Status)
+		}
+
+		job.Status = StatusCancelled
+
+		return nil
+	})
+}
+
+func (q *queue) Retry(id string) (Job, error) {
+	return q.transition(id, func(job *Job) error {
+		if job.Status != StatusFailed && job.Status != StatusCancelled {
+			return fmt.Errorf("%w: cannot retry job %s, which is %s", ErrInvalidState, job.ID, job.Status)
+		}
+
+		// A running job which was cancelled is only finished once its run stops, so that it never runs twice.
+		if job.Status == StatusCancelled && job.FinishedAt.IsZero() {
+			return fmt.Errorf("%w: cannot retry job %s until its cancellation is complete", ErrInvalidState, job.ID)
+		}
+
+		job.Status = StatusQueued
+		job.StartedAt = time.Time{}
+		job.FinishedAt = time.Time{}
+
+		return nil
+	})
+}
+
+// transition applies fn to the stored job with the specified ID.
+func (q *queue) transition(id string, fn func(job *Job) error) (Job, error) {
+	var result Job
+
+	err := q.update(func(jobs []Job) ([]Job, error) {
+		index, err := find(jobs, id)
+		if err != nil {
+			return nil, err
+		}
+
+		err = fn(&jobs[index])
+		if err != nil {
+			return nil, err
+		}
+
+		result = jobs[index]
+
+		return jobs, nil
+	})
+	if err != nil {
+		return Job{}, err
+	}
+
+	q.notify()
+
+	return result, nil
+}
+
+func newJobID() (string, error) {
+	id := make([]byte, 8)
+
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate job id: %w", err)
+	}
+
+	return hex.EncodeToString(id), nil
+}
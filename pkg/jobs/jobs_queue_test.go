@@ -0,0 +1,352 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/majd/ipatool/v2/pkg/util/filelock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("Queue", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockRunner *MockRunner
+		path       string
+		q          Queue
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRunner = NewMockRunner(ctrl)
+		path = filepath.Join(GinkgoT().TempDir(), "nested", "jobs.json")
+		q = New(Args{
+			Path:         path,
+			Runner:       mockRunner,
+			PollInterval: 10 * time.Millisecond,
+		})
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	status := func(id string) func() Status {
+		return func() Status {
+			job, err := q.Get(id)
+			Expect(err).ToNot(HaveOccurred())
+
+			return job.Status
+		}
+	}
+
+	When("a job is enqueued", func() {
+		It("persists the job", func() {
+			job, err := q.Enqueue(Request{BundleID: "app.bundle.id", Platform: "ios", OutputPath: "/tmp", Account: "user@example.com"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(job.ID).To(HaveLen(16))
+			Expect(job.Status).To(Equal(StatusQueued))
+
+			jobs, err := New(Args{Path: path}).List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(jobs).To(Equal([]Job{job}))
+		})
+	})
+
+	When("the job does not exist", func() {
+		It("returns error", func() {
+			_, err := q.Get("missing")
+			Expect(err).To(MatchError(ErrJobNotFound))
+
+			_, err = q.Cancel("missing")
+			Expect(err).To(MatchError(ErrJobNotFound))
+
+			_, err = q.Retry("missing")
+			Expect(err).To(MatchError(ErrJobNotFound))
+		})
+	})
+
+	When("the store is invalid", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+			Expect(os.WriteFile(path, []byte("{"), 0600)).To(Succeed())
+		})
+
+		It("returns error", func() {
+			_, err := q.List()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("running pending jobs", func() {
+		It("records the outcome of each job", func() {
+			succeeding, err := q.Enqueue(Request{BundleID: "app.succeeds"})
+			Expect(err).ToNot(HaveOccurred())
+
+			failing, err := q.Enqueue(Request{BundleID: "app.fails"})
+			Expect(err).ToNot(HaveOccurred())
+
+			mockRunner.EXPECT().
+				Run(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, job Job) (Result, error) {
+					Expect(job.Status).To(Equal(StatusRunning))
+					Expect(job.Attempts).To(Equal(1))
+
+					if job.ID == failing.ID {
+						return Result{}, errors.New("something went wrong")
+					}
+
+					return Result{Output: "/tmp/app.ipa", Purchased: true}, nil
+				}).
+				Times(2)
+
+			Expect(q.RunPending(context.Background())).To(Succeed())
+
+			job, err := q.Get(succeeding.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(job.Status).To(Equal(StatusSucceeded))
+			Expect(job.Output).To(Equal("/tmp/app.ipa"))
+			Expect(job.Purchased).To(BeTrue())
+			Expect(job.FinishedAt).ToNot(BeZero())
+
+			job, err = q.Get(failing.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(job.Status).To(Equal(StatusFailed))
+			Expect(job.LastError).To(Equal("something went wrong"))
+			Expect(job.LastErrorCode).To(Equal("unknown"))
+		})
+	})
+
+	When("a job is left running by a process which stopped", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+			Expect(os.WriteFile(path, []byte(`{"jobs":[{"id":"orphan","status":"running","attempts":1}]}`), 0600)).To(Succeed())
+
+			mockRunner.EXPECT().
+				Run(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, job Job) (Result, error) {
+					Expect(job.Attempts).To(Equal(2))
+
+					return Result{Output: "/tmp/app.ipa"}, nil
+				})
+		})
+
+		It("resumes the job", func() {
+			Expect(q.RunPending(context.Background())).To(Succeed())
+			Expect(status("orphan")()).To(Equal(StatusSucceeded))
+		})
+	})
+
+	When("another process runs the jobs", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+
+			lock, err := filelock.Lock(path+".runner.lock", false)
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(filelock.Unlock, lock)
+		})
+
+		It("returns error", func() {
+			Expect(q.RunPending(context.Background())).To(MatchError(ErrRunnerActive))
+		})
+	})
+
+	When("a running job is cancelled", func() {
+		It("stops the job", func() {
+			job, err := q.Enqueue(Request{BundleID: "app.bundle.id"})
+			Expect(err).ToNot(HaveOccurred())
+
+			mockRunner.EXPECT().
+				Run(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, job Job) (Result, error) {
+					// The job is cancelled by another process.
+					_, err := New(Args{Path: path}).Cancel(job.ID)
+					Expect(err).ToNot(HaveOccurred())
+
+					<-ctx.Done()
+
+					return Result{}, ctx.Err()
+				})
+
+			Expect(q.RunPending(context.Background())).To(Succeed())
+
+			job, err = q.Get(job.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(job.Status).To(Equal(StatusCancelled))
+			Expect(job.FinishedAt).ToNot(BeZero())
+
+			job, err = q.Retry(job.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(job.Status).To(Equal(StatusQueued))
+			Expect(job.FinishedAt).To(BeZero())
+		})
+	})
+
+	When("a running job is cancelled and retried", func() {
+		BeforeEach(func() {
+			q = New(Args{Path: path, Runner: mockRunner, Concurrency: 2, PollInterval: 10 * time.Millisecond})
+		})
+
+		It("rejects the retry until the run stops", func() {
+			job, err := q.Enqueue(Request{BundleID: "app.bundle.id"})
+			Expect(err).ToNot(HaveOccurred())
+
+			mockRunner.EXPECT().
+				Run(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, job Job) (Result, error) {
+					other := New(Args{Path: path})
+
+					_, err := other.Cancel(job.ID)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, err = other.Retry(job.ID)
+					Expect(err).To(MatchError(ErrInvalidState))
+
+					<-ctx.Done()
+
+					return Result{}, ctx.Err()
+				})
+
+			Expect(q.RunPending(context.Background())).To(Succeed())
+			Expect(status(job.ID)()).To(Equal(StatusCancelled))
+
+			_, err = q.Retry(job.ID)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("allows the retry once another runner takes over after a crash", func() {
+			job, err := q.Enqueue(Request{BundleID: "app.bundle.id"})
+			Expect(err).ToNot(HaveOccurred())
+
+			// The job was claimed by a runner which crashed after the job was cancelled.
+			Expect(q.(*queue).update(func(jobs []Job) ([]Job, error) {
+				jobs[0].Status = StatusRunning
+
+				return jobs, nil
+			})).To(Succeed())
+
+			_, err = q.Cancel(job.ID)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = q.Retry(job.ID)
+			Expect(err).To(MatchError(ErrInvalidState))
+
+			Expect(New(Args{Path: path, Runner: mockRunner}).RunPending(context.Background())).To(Succeed())
+
+			job, err = q.Get(job.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(job.Status).To(Equal(StatusCancelled))
+			Expect(job.FinishedAt).ToNot(BeZero())
+
+			job, err = q.Retry(job.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(job.Status).To(Equal(StatusQueued))
+		})
+
+		It("does not start the job again while it runs", func() {
+			job, err := q.Enqueue(Request{BundleID: "app.bundle.id"})
+			Expect(err).ToNot(HaveOccurred())
+
+			mockRunner.EXPECT().
+				Run(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, job Job) (Result, error) {
+					// The job is queued again while running, e.g. by an older version of the tool.
+					err := New(Args{Path: path}).(*queue).update(func(jobs []Job) ([]Job, error) {
+						jobs[0].Status = StatusQueued
+
+						return jobs, nil
+					})
+					Expect(err).ToNot(HaveOccurred())
+
+					// Leave time for the runner to poll the queue.
+					time.Sleep(50 * time.Millisecond)
+
+					return Result{}, nil
+				}).
+				Times(1)
+
+			Expect(q.RunPending(context.Background())).To(Succeed())
+			Expect(status(job.ID)()).To(Equal(StatusSucceeded))
+		})
+	})
+
+	When("the queue stops while a job is running", func() {
+		It("queues the job again", func() {
+			job, err := q.Enqueue(Request{BundleID: "app.bundle.id"})
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+
+			mockRunner.EXPECT().
+				Run(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, job Job) (Result, error) {
+					cancel()
+					<-ctx.Done()
+
+					return Result{}, ctx.Err()
+				})
+
+			Expect(q.Run(ctx)).To(Succeed())
+			Expect(status(job.ID)()).To(Equal(StatusQueued))
+		})
+	})
+
+	When("running jobs concurrently", func() {
+		BeforeEach(func() {
+			q = New(Args{Path: path, Runner: mockRunner, Concurrency: 2})
+		})
+
+		It("runs up to the configured number of jobs at once", func() {
+			for range 3 {
+				_, err := q.Enqueue(Request{BundleID: "app.bundle.id"})
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			running := make(chan struct{}, 3)
+			release := make(chan struct{})
+
+			mockRunner.EXPECT().
+				Run(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, job Job) (Result, error) {
+					running <- struct{}{}
+					<-release
+
+					return Result{}, nil
+				}).
+				Times(3)
+
+			done := make(chan error)
+
+			go func() {
+				done <- q.RunPending(context.Background())
+			}()
+
+			Eventually(running).Should(HaveLen(2))
+			Consistently(running, 50*time.Millisecond).Should(HaveLen(2))
+
+			close(release)
+			Eventually(done).Should(Receive(BeNil()))
+			Expect(running).To(HaveLen(3))
+		})
+	})
+
+	DescribeTable("invalid transitions",
+		func(status Status, transition func(q Queue, id string) (Job, error)) {
+			Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+			Expect(os.WriteFile(path, []byte(`{"jobs":[{"id":"job","status":"`+string(status)+`"}]}`), 0600)).To(Succeed())
+
+			_, err := transition(q, "job")
+			Expect(err).To(MatchError(ErrInvalidState))
+		},
+		Entry("cancelling a succeeded job", StatusSucceeded, Queue.Cancel),
+		Entry("cancelling a failed job", StatusFailed, Queue.Cancel),
+		Entry("retrying a queued job", StatusQueued, Queue.Retry),
+		Entry("retrying a running job", StatusRunning, Queue.Retry),
+		Entry("retrying a succeeded job", StatusSucceeded, Queue.Retry),
+	)
+})
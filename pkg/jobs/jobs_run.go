@@ -0,0 +1,209 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/majd/ipatool/v2/pkg/util/filelock"
+)
+
+type outcome struct {
+	id     string
+	result Result
+	err    error
+}
+
+func (q *queue) Run(ctx context.Context) error {
+	return q.run(ctx, false)
+}
+
+func (q *queue) RunPending(ctx context.Context) error {
+	return q.run(ctx, true)
+}
+
+// nolint:cyclop
+func (q *queue) run(ctx context.Context, untilIdle bool) error {
+	lock, err := filelock.Lock(q.path+".runner.lock", false)
+	if errors.Is(err, filelock.ErrLocked) {
+		return ErrRunnerActive
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to lock jobs runner: %w", err)
+	}
+	defer filelock.Unlock(lock)
+
+	err = q.requeueOrphans()
+	if err != nil {
+		return err
+	}
+
+	active := map[string]context.CancelFunc{}
+	outcomes := make(chan outcome)
+
+	for {
+		jobs, cancelled, queued, err := q.claim(active)
+		if err != nil {
+			_ = q.stop(active, outcomes)
+
+			return err
+		}
+
+		for _, id := range cancelled {
+			active[id]()
+		}
+
+		for _, job := range jobs {
+			jobCtx, cancel := context.WithCancel(ctx)
+			active[job.ID] = cancel
+
+			go func(job Job) {
+				result, err := q.runner.Run(jobCtx, job)
+				outcomes <- outcome{id: job.ID, result: result, err: err}
+			}(job)
+		}
+
+		if untilIdle && len(active) == 0 && queued == 0 {
+			return nil
+		}
+
+		select {
+		case o := <-outcomes:
+			delete(active, o.id)
+
+			err = q.finish(o, ctx.Err() != nil)
+			if err != nil {
+				_ = q.stop(active, outcomes)
+
+				return err
+			}
+		case <-q.wake:
+		case <-time.After(q.pollInterval):
+		case <-ctx.Done():
+			return q.stop(active, outcomes)
+		}
+	}
+}
+
+// requeueOrphans queues the jobs left running by a process which stopped without finishing them, and
+// finishes the jobs whose cancellation that process did not pick up.
+func (q *queue) requeueOrphans() error {
+	return q.update(func(jobs []Job) ([]Job, error) {
+		for i := range jobs {
+			switch {
+			case jobs[i].Status == StatusRunning:
+				jobs[i].Status = StatusQueued
+			case jobs[i].Status == StatusCancelled && jobs[i].FinishedAt.IsZero():
+				jobs[i].FinishedAt = now()
+			}
+		}
+
+		return jobs, nil
+	})
+}
+
+// claim marks queued jobs as running, up to the concurrency of the queue, and returns them along with
+// the active jobs which were cancelled meanwhile and the number of jobs still queued.
+func (q *queue) claim(active map[string]context.CancelFunc) ([]Job, []string, int, error) {
+	var (
+		claimed   []Job
+		cancelled []string
+		queued    int
+	)
+
+	err := q.update(func(jobs []Job) ([]Job, error) {
+		for i := range jobs {
+			_, running := active[jobs[i].ID]
+			if running && jobs[i].Status == StatusCancelled {
+				cancelled = append(cancelled, jobs[i].ID)
+			}
+
+			if jobs[i].Status != StatusQueued {
+				continue
+			}
+
+			// A job is never started again while a previous run of it is still active.
+			if running {
+				queued++
+
+				continue
+			}
+
+			if len(active)+len(claimed) == q.concurrency {
+				queued++
+
+				continue
+			}
+
+			jobs[i].Status = StatusRunning
+			jobs[i].Attempts++
+			jobs[i].StartedAt = now()
+			jobs[i].FinishedAt = time.Time{}
+			claimed = append(claimed, jobs[i])
+		}
+
+		if len(claimed) == 0 {
+			return nil, nil
+		}
+
+		return jobs, nil
+	})
+
+	return claimed, cancelled, queued, err
+}
+
+// finish records the outcome of a job. Jobs which failed because the queue was stopped are queued again
+// so that the next run resumes them.
+func (q *queue) finish(o outcome, interrupted bool) error {
+	return q.update(func(jobs []Job) ([]Job, error) {
+		index, err := find(jobs, o.id)
+		if err != nil {
+			// The job was removed from the store while running.
+			return nil, nil
+		}
+
+		job := &jobs[index]
+
+		switch {
+		case job.Status == StatusCancelled:
+			job.FinishedAt = now()
+		case interrupted && o.err != nil:
+			job.Status = StatusQueued
+		case o.err != nil:
+			job.Status = StatusFailed
+			job.LastError = o.err.Error()
+			job.LastErrorCode = string(appstore.ErrorCodeOf(o.err))
+			job.FinishedAt = now()
+		default:
+			job.Status = StatusSucceeded
+			job.Output = o.result.Output
+			job.Purchased = o.result.Purchased
+			job.LastError = ""
+			job.LastErrorCode = ""
+			job.FinishedAt = now()
+		}
+
+		return jobs, nil
+	})
+}
+
+// stop cancels the active jobs and waits for them to return before queuing them again.
+func (q *queue) stop(active map[string]context.CancelFunc, outcomes chan outcome) error {
+	for _, cancel := range active {
+		cancel()
+	}
+
+	var errs []error
+
+	for len(active) > 0 {
+		o := <-outcomes
+		delete(active, o.id)
+
+		errs = append(errs, q.finish(o, true))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,110 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/majd/ipatool/v2/pkg/util"
+	"github.com/majd/ipatool/v2/pkg/util/filelock"
+)
+
+type storeFile struct {
+	Jobs []Job `json:"jobs"`
+}
+
+// read returns the stored jobs while holding the lock of the store.
+func (q *queue) read() ([]Job, error) {
+	var result []Job
+
+	err := q.update(func(jobs []Job) ([]Job, error) {
+		result = jobs
+
+		return nil, nil
+	})
+
+	return result, err
+}
+
+// update replaces the stored jobs with the jobs returned by fn while holding the lock of the store. The
+// jobs are left unchanged when fn returns nil jobs.
+func (q *queue) update(fn func(jobs []Job) ([]Job, error)) error {
+	err := os.MkdirAll(filepath.Dir(q.path), 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create jobs directory: %w", err)
+	}
+
+	lock, err := filelock.Lock(q.path+".lock", true)
+	if err != nil {
+		return fmt.Errorf("failed to lock jobs: %w", err)
+	}
+	defer filelock.Unlock(lock)
+
+	jobs, err := q.load()
+	if err != nil {
+		return err
+	}
+
+	updated, err := fn(jobs)
+	if err != nil {
+		return err
+	}
+
+	if updated == nil {
+		return nil
+	}
+
+	return q.save(updated)
+}
+
+func (q *queue) load() ([]Job, error) {
+	data, err := os.ReadFile(q.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Job{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jobs: %w", err)
+	}
+
+	var file storeFile
+
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jobs: %w", err)
+	}
+
+	if file.Jobs == nil {
+		file.Jobs = []Job{}
+	}
+
+	return file.Jobs, nil
+}
+
+// save writes the jobs atomically, so that the store is never left partially written.
+func (q *queue) save(jobs []Job) error {
+	data, err := json.MarshalIndent(storeFile{Jobs: jobs}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal jobs: %w", err)
+	}
+
+	err = util.WriteFileAtomic(q.path, data, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write jobs: %w", err)
+	}
+
+	return nil
+}
+
+// find returns the index of the job with the specified ID.
+func find(jobs []Job, id string) (int, error) {
+	for i, job := range jobs {
+		if job.ID == id {
+			return i, nil
+		}
+	}
+
+	return -1, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+}
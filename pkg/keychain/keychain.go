@@ -5,6 +5,14 @@ type Keychain interface {
 	Get(key string) ([]byte, error)
 	Set(key string, data []byte) error
 	Remove(key string) error
+	// Keys returns the keys of the items stored by ipatool, ignoring unrelated items of the keyring, e.g.
+	// the other files of the directory of the file backend.
+	Keys() ([]string, error)
+}
+
+// ownedKeys are the keys of the items ipatool stores in the keychain.
+var ownedKeys = map[string]bool{
+	"account": true,
 }
 
 type keychain struct {
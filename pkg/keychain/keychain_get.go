@@ -1,11 +1,21 @@
 package keychain
 
 import (
+	"errors"
 	"fmt"
+
+	"github.com/byteness/keyring"
 )
 
+// ErrItemNotFound is returned when the keychain does not hold an item with the requested key.
+var ErrItemNotFound = errors.New("item not found")
+
 func (k *keychain) Get(key string) ([]byte, error) {
 	item, err := k.keyring.Get(key)
+	if errors.Is(err, keyring.ErrKeyNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrItemNotFound, key)
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("failed to get item: %w", err)
 	}
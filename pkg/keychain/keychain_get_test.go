@@ -44,6 +44,22 @@ var _ = Describe("Keychain (Get)", func() {
 		})
 	})
 
+	When("keyring does not hold the item", func() {
+		const testKey = "test-key"
+
+		BeforeEach(func() {
+			mockKeyring.EXPECT().
+				Get(testKey).
+				Return(keyring.Item{}, keyring.ErrKeyNotFound)
+		})
+
+		It("returns item not found error", func() {
+			data, err := keychain.Get(testKey)
+			Expect(err).To(MatchError(ErrItemNotFound))
+			Expect(data).To(BeNil())
+		})
+	})
+
 	When("keyring returns item", func() {
 		const testKey = "test-key"
 		var testData = []byte("test")
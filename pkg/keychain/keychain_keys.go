@@ -0,0 +1,22 @@
+package keychain
+
+import (
+	"fmt"
+)
+
+func (k *keychain) Keys() ([]string, error) {
+	keys, err := k.keyring.Keys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+
+	owned := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		if ownedKeys[key] {
+			owned = append(owned, key)
+		}
+	}
+
+	return owned, nil
+}
@@ -0,0 +1,71 @@
+package keychain
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("Keychain (Keys)", func() {
+	var (
+		ctrl        *gomock.Controller
+		keychain    Keychain
+		mockKeyring *MockKeyring
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockKeyring = NewMockKeyring(ctrl)
+		keychain = New(Args{
+			Keyring: mockKeyring,
+		})
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	When("keyring returns error", func() {
+		BeforeEach(func() {
+			mockKeyring.EXPECT().
+				Keys().
+				Return(nil, errors.New(""))
+		})
+
+		It("returns wrapped error", func() {
+			keys, err := keychain.Keys()
+			Expect(err).To(HaveOccurred())
+			Expect(keys).To(BeNil())
+		})
+	})
+
+	When("keyring returns keys", func() {
+		BeforeEach(func() {
+			mockKeyring.EXPECT().
+				Keys().
+				Return([]string{"account"}, nil)
+		})
+
+		It("returns keys", func() {
+			keys, err := keychain.Keys()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(keys).To(Equal([]string{"account"}))
+		})
+	})
+
+	When("keyring returns unrelated keys", func() {
+		BeforeEach(func() {
+			mockKeyring.EXPECT().
+				Keys().
+				Return([]string{"cookies", "account", "config.yaml", "jobs.json", "library"}, nil)
+		})
+
+		It("returns only the keys of ipatool items", func() {
+			keys, err := keychain.Keys()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(keys).To(Equal([]string{"account"}))
+		})
+	})
+})
@@ -7,4 +7,5 @@ type Keyring interface {
 	Get(key string) (keyring.Item, error)
 	Set(item keyring.Item) error
 	Remove(key string) error
+	Keys() ([]string, error)
 }
@@ -0,0 +1,226 @@
+package keychain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"filippo.io/age"
+	"github.com/byteness/keyring"
+	"github.com/majd/ipatool/v2/pkg/util"
+)
+
+var (
+	ErrAgeIdentityRequired  = errors.New("an age identity is required to read the keychain")
+	ErrAgeRecipientRequired = errors.New("an age recipient is required to write the keychain")
+)
+
+type ageKeyring struct {
+	path       string
+	identities []age.Identity
+	recipients []age.Recipient
+	mutex      sync.Mutex
+}
+
+type AgeKeyringArgs struct {
+	// Path is the location of the encrypted keychain file.
+	Path string
+	// IdentityFile is the path of a file containing age identities used to decrypt the keychain.
+	IdentityFile string
+	// Recipients are the age public keys the keychain is encrypted to. When empty, the recipients
+	// are derived from the X25519 identities.
+	Recipients []string
+}
+
+// NewAgeKeyring returns a keyring that stores every item in a single file encrypted with age.
+func NewAgeKeyring(args AgeKeyringArgs) (Keyring, error) {
+	var (
+		identities []age.Identity
+		recipients []age.Recipient
+	)
+
+	if args.IdentityFile != "" {
+		data, err := os.ReadFile(args.IdentityFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read identity file: %w", err)
+		}
+
+		identities, err = age.ParseIdentities(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse identities: %w", err)
+		}
+	}
+
+	if len(args.Recipients) > 0 {
+		parsed, err := age.ParseRecipients(strings.NewReader(strings.Join(args.Recipients, "\n")))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse recipients: %w", err)
+		}
+
+		recipients = parsed
+	} else {
+		for _, identity := range identities {
+			if x25519, ok := identity.(*age.X25519Identity); ok {
+				recipients = append(recipients, x25519.Recipient())
+			}
+		}
+	}
+
+	if len(identities) == 0 && len(recipients) == 0 {
+		return nil, errors.New("either an age identity file or a recipient must be specified")
+	}
+
+	return &ageKeyring{
+		path:       args.Path,
+		identities: identities,
+		recipients: recipients,
+	}, nil
+}
+
+func (k *ageKeyring) Get(key string) (keyring.Item, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	items, err := k.load()
+	if err != nil {
+		return keyring.Item{}, err
+	}
+
+	data, ok := items[key]
+	if !ok {
+		return keyring.Item{}, keyring.ErrKeyNotFound
+	}
+
+	return keyring.Item{
+		Key:  key,
+		Data: data,
+	}, nil
+}
+
+func (k *ageKeyring) Set(item keyring.Item) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	items, err := k.load()
+	if err != nil {
+		return err
+	}
+
+	items[item.Key] = item.Data
+
+	return k.save(items)
+}
+
+func (k *ageKeyring) Remove(key string) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	items, err := k.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := items[key]; !ok {
+		return keyring.ErrKeyNotFound
+	}
+
+	delete(items, key)
+
+	return k.save(items)
+}
+
+func (k *ageKeyring) Keys() ([]string, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	items, err := k.load()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+func (k *ageKeyring) load() (map[string][]byte, error) {
+	items := map[string][]byte{}
+
+	file, err := os.Open(k.path)
+	if os.IsNotExist(err) {
+		return items, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to open keychain file: %w", err)
+	}
+	defer file.Close()
+
+	if len(k.identities) == 0 {
+		return nil, ErrAgeIdentityRequired
+	}
+
+	reader, err := age.Decrypt(file, k.identities...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keychain file: %w", err)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keychain file: %w", err)
+	}
+
+	err = json.Unmarshal(data, &items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal keychain file: %w", err)
+	}
+
+	return items, nil
+}
+
+func (k *ageKeyring) save(items map[string][]byte) error {
+	if len(k.recipients) == 0 {
+		return ErrAgeRecipientRequired
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal keychain items: %w", err)
+	}
+
+	buffer := new(bytes.Buffer)
+
+	writer, err := age.Encrypt(buffer, k.recipients...)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt keychain items: %w", err)
+	}
+
+	_, err = writer.Write(data)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt keychain items: %w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("failed to encrypt keychain items: %w", err)
+	}
+
+	// Write atomically so that a failed write never corrupts the existing keychain.
+	err = util.WriteFileAtomic(k.path, buffer.Bytes(), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write keychain file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package keychain
+
+import (
+	"os"
+	"path/filepath"
+
+	"filippo.io/age"
+	"github.com/byteness/keyring"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Keyring (Age)", func() {
+	var (
+		dir          string
+		path         string
+		identity     *age.X25519Identity
+		identityFile string
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		dir = GinkgoT().TempDir()
+		path = filepath.Join(dir, "keychain.age")
+		identityFile = filepath.Join(dir, "identity.txt")
+
+		identity, err = age.GenerateX25519Identity()
+		Expect(err).ToNot(HaveOccurred())
+
+		err = os.WriteFile(identityFile, []byte(identity.String()+"\n"), 0600)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	When("neither identity nor recipient is specified", func() {
+		It("returns error", func() {
+			_, err := NewAgeKeyring(AgeKeyringArgs{Path: path})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("identity file does not exist", func() {
+		It("returns error", func() {
+			_, err := NewAgeKeyring(AgeKeyringArgs{Path: path, IdentityFile: filepath.Join(dir, "missing")})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("recipient is invalid", func() {
+		It("returns error", func() {
+			_, err := NewAgeKeyring(AgeKeyringArgs{Path: path, Recipients: []string{"invalid"}})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("identity file is specified", func() {
+		var ring Keyring
+
+		BeforeEach(func() {
+			var err error
+			ring, err = NewAgeKeyring(AgeKeyringArgs{Path: path, IdentityFile: identityFile})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns not found for missing item", func() {
+			_, err := ring.Get("account")
+			Expect(err).To(MatchError(keyring.ErrKeyNotFound))
+		})
+
+		It("stores and retrieves items", func() {
+			err := ring.Set(keyring.Item{Key: "account", Data: []byte("test")})
+			Expect(err).ToNot(HaveOccurred())
+
+			item, err := ring.Get("account")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(item.Data).To(Equal([]byte("test")))
+		})
+
+		It("encrypts the file", func() {
+			err := ring.Set(keyring.Item{Key: "account", Data: []byte("secret-value")})
+			Expect(err).ToNot(HaveOccurred())
+
+			data, err := os.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).ToNot(ContainSubstring("secret-value"))
+		})
+
+		It("lists keys", func() {
+			err := ring.Set(keyring.Item{Key: "b", Data: []byte("test")})
+			Expect(err).ToNot(HaveOccurred())
+
+			err = ring.Set(keyring.Item{Key: "a", Data: []byte("test")})
+			Expect(err).ToNot(HaveOccurred())
+
+			keys, err := ring.Keys()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(keys).To(Equal([]string{"a", "b"}))
+		})
+
+		It("removes items", func() {
+			err := ring.Set(keyring.Item{Key: "account", Data: []byte("test")})
+			Expect(err).ToNot(HaveOccurred())
+
+			err = ring.Remove("account")
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = ring.Get("account")
+			Expect(err).To(MatchError(keyring.ErrKeyNotFound))
+		})
+
+		It("returns not found when removing missing item", func() {
+			err := ring.Remove("account")
+			Expect(err).To(MatchError(keyring.ErrKeyNotFound))
+		})
+	})
+
+	When("only a recipient is specified", func() {
+		var ring Keyring
+
+		BeforeEach(func() {
+			var err error
+			ring, err = NewAgeKeyring(AgeKeyringArgs{Path: path, Recipients: []string{identity.Recipient().String()}})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("can write to a new keychain", func() {
+			err := ring.Set(keyring.Item{Key: "account", Data: []byte("test")})
+			Expect(err).ToNot(HaveOccurred())
+
+			reader, err := NewAgeKeyring(AgeKeyringArgs{Path: path, IdentityFile: identityFile})
+			Expect(err).ToNot(HaveOccurred())
+
+			item, err := reader.Get("account")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(item.Data).To(Equal([]byte("test")))
+		})
+
+		It("cannot read an existing keychain", func() {
+			err := ring.Set(keyring.Item{Key: "account", Data: []byte("test")})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = ring.Get("account")
+			Expect(err).To(MatchError(ErrAgeIdentityRequired))
+		})
+	})
+})
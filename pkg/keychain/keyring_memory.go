@@ -0,0 +1,97 @@
+package keychain
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/byteness/keyring"
+)
+
+// EnvironmentItemPrefix is the prefix of environment variables seeding the in-memory keyring. The rest of
+// the variable name, lowercased, is used as the item key (e.g. IPATOOL_KEYCHAIN_ACCOUNT seeds "account").
+const EnvironmentItemPrefix = "IPATOOL_KEYCHAIN_"
+
+type memoryKeyring struct {
+	items map[string][]byte
+	mutex sync.RWMutex
+}
+
+// NewMemoryKeyring returns a keyring that keeps items in memory only. Changes are lost when the process exits.
+func NewMemoryKeyring(items map[string][]byte) Keyring {
+	copied := make(map[string][]byte, len(items))
+	for key, data := range items {
+		copied[key] = data
+	}
+
+	return &memoryKeyring{
+		items: copied,
+	}
+}
+
+// ItemsFromEnvironment extracts keyring items from environment variables in "KEY=value" form.
+func ItemsFromEnvironment(environ []string) map[string][]byte {
+	items := map[string][]byte{}
+
+	for _, variable := range environ {
+		name, value, ok := strings.Cut(variable, "=")
+		if !ok || !strings.HasPrefix(name, EnvironmentItemPrefix) || len(name) == len(EnvironmentItemPrefix) {
+			continue
+		}
+
+		items[strings.ToLower(strings.TrimPrefix(name, EnvironmentItemPrefix))] = []byte(value)
+	}
+
+	return items
+}
+
+func (k *memoryKeyring) Get(key string) (keyring.Item, error) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	data, ok := k.items[key]
+	if !ok {
+		return keyring.Item{}, keyring.ErrKeyNotFound
+	}
+
+	return keyring.Item{
+		Key:  key,
+		Data: data,
+	}, nil
+}
+
+func (k *memoryKeyring) Set(item keyring.Item) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	k.items[item.Key] = item.Data
+
+	return nil
+}
+
+func (k *memoryKeyring) Remove(key string) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if _, ok := k.items[key]; !ok {
+		return keyring.ErrKeyNotFound
+	}
+
+	delete(k.items, key)
+
+	return nil
+}
+
+func (k *memoryKeyring) Keys() ([]string, error) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	keys := make([]string, 0, len(k.items))
+	for key := range k.items {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
@@ -0,0 +1,72 @@
+package keychain
+
+import (
+	"github.com/byteness/keyring"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Keyring (Memory)", func() {
+	var ring Keyring
+
+	BeforeEach(func() {
+		ring = NewMemoryKeyring(map[string][]byte{
+			"account": []byte("test"),
+		})
+	})
+
+	It("returns seeded items", func() {
+		item, err := ring.Get("account")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(item.Data).To(Equal([]byte("test")))
+	})
+
+	It("returns not found for missing item", func() {
+		_, err := ring.Get("missing")
+		Expect(err).To(MatchError(keyring.ErrKeyNotFound))
+	})
+
+	It("stores items", func() {
+		err := ring.Set(keyring.Item{Key: "other", Data: []byte("value")})
+		Expect(err).ToNot(HaveOccurred())
+
+		item, err := ring.Get("other")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(item.Data).To(Equal([]byte("value")))
+	})
+
+	It("lists keys", func() {
+		err := ring.Set(keyring.Item{Key: "other", Data: []byte("value")})
+		Expect(err).ToNot(HaveOccurred())
+
+		keys, err := ring.Keys()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(keys).To(Equal([]string{"account", "other"}))
+	})
+
+	It("removes items", func() {
+		err := ring.Remove("account")
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = ring.Get("account")
+		Expect(err).To(MatchError(keyring.ErrKeyNotFound))
+
+		err = ring.Remove("account")
+		Expect(err).To(MatchError(keyring.ErrKeyNotFound))
+	})
+
+	When("reading items from the environment", func() {
+		It("only uses prefixed variables", func() {
+			items := ItemsFromEnvironment([]string{
+				"HOME=/root",
+				"IPATOOL_KEYCHAIN_ACCOUNT={\"email\":\"test\"}",
+				"IPATOOL_KEYCHAIN_=ignored",
+				"IPATOOL_KEYCHAIN_OTHER=a=b",
+			})
+			Expect(items).To(Equal(map[string][]byte{
+				"account": []byte("{\"email\":\"test\"}"),
+				"other":   []byte("a=b"),
+			}))
+		})
+	})
+})
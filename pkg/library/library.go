@@ -0,0 +1,112 @@
+package library
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrPackageNotFound = errors.New("no app package in the library matches the hash")
+	ErrAmbiguousHash   = errors.New("several app packages in the library match the hash")
+)
+
+// Package describes an app package stored in the library.
+type Package struct {
+	Hash              string    `json:"hash" description:"SHA-256 digest of the app package"`
+	Size              int64     `json:"size" description:"size of the app package in bytes"`
+	BundleID          string    `json:"bundleID,omitempty"`
+	AppID             int64     `json:"appID,omitempty"`
+	Name              string    `json:"name,omitempty"`
+	DisplayVersion    string    `json:"displayVersion,omitempty"`
+	ExternalVersionID string    `json:"externalVersionID,omitempty"`
+	Platform          string    `json:"platform,omitempty"`
+	Account           string    `json:"account,omitempty" description:"email of the account the app package was downloaded with"`
+	DownloadedAt      time.Time `json:"downloadedAt"`
+}
+
+// Metadata describes an app package being added to the library. Empty fields are read from the
+// iTunesMetadata.plist and Info.plist files of the package.
+type Metadata struct {
+	BundleID          string
+	AppID             int64
+	Name              string
+	DisplayVersion    string
+	ExternalVersionID string
+	Platform          string
+	Account           string
+	// DownloadedAt defaults to the modification time of the package.
+	DownloadedAt time.Time
+}
+
+// Query selects the entries whose fields are equal to the non-empty fields of the query.
+type Query struct {
+	BundleID          string
+	AppID             int64
+	DisplayVersion    string
+	ExternalVersionID string
+	Platform          string
+	Account           string
+}
+
+type AddInput struct {
+	// Path is the path of the app package to add.
+	Path     string
+	Metadata Metadata
+	// Move removes the app package from its path once it is stored in the library.
+	Move bool
+}
+
+type AddOutput struct {
+	Package Package
+	// Duplicate is true when an identical app package was already stored in the library, in which case
+	// the existing entry is returned.
+	Duplicate bool
+}
+
+// Library stores app packages under their content hash, along with an index of their metadata.
+//
+//go:generate go run go.uber.org/mock/mockgen -source=library.go -destination=library_mock.go -package library
+type Library interface {
+	// Root returns the directory the library is stored in.
+	Root() string
+	// Add stores an app package in the library, unless an identical package is already stored.
+	Add(input AddInput) (AddOutput, error)
+	// List returns the entries in the order they were added.
+	List() ([]Package, error)
+	// Find returns the entries matching the query in the order they were added.
+	Find(query Query) ([]Package, error)
+	// Remove removes the app package whose hash starts with the specified prefix.
+	Remove(hash string) (Package, error)
+	// Path returns the path of the app package whose hash starts with the specified prefix.
+	Path(hash string) (string, error)
+	// GC removes the app packages which are not kept by the retention policy.
+	GC(input GCInput) (GCOutput, error)
+}
+
+type library struct {
+	root string
+}
+
+type Args struct {
+	// Root is the directory the library is stored in. It is created when the first package is added.
+	Root string
+}
+
+func New(args Args) Library {
+	return &library{
+		root: args.Root,
+	}
+}
+
+func (l *library) Root() string {
+	return l.root
+}
+
+func (q Query) matches(entry Package) bool {
+	return (q.BundleID == "" || q.BundleID == entry.BundleID) &&
+		(q.AppID == 0 || q.AppID == entry.AppID) &&
+		(q.DisplayVersion == "" || q.DisplayVersion == entry.DisplayVersion) &&
+		(q.ExternalVersionID == "" || q.ExternalVersionID == entry.ExternalVersionID) &&
+		(q.Platform == "" || q.Platform == entry.Platform) &&
+		(q.Account == "" || q.Account == entry.Account)
+}
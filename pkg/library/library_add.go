@@ -0,0 +1,184 @@
+package library
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+func (l *library) Add(input AddInput) (AddOutput, error) {
+	info, err := os.Stat(input.Path)
+	if err != nil {
+		return AddOutput{}, fmt.Errorf("failed to read app package metadata: %w", err)
+	}
+
+	hash, err := hashFile(input.Path)
+	if err != nil {
+		return AddOutput{}, err
+	}
+
+	metadata, err := readMetadata(input.Path)
+	if err != nil {
+		return AddOutput{}, err
+	}
+
+	entry := Package{
+		Hash:              hash,
+		Size:              info.Size(),
+		BundleID:          firstNonEmpty(input.Metadata.BundleID, metadata.BundleID),
+		AppID:             input.Metadata.AppID,
+		Name:              firstNonEmpty(input.Metadata.Name, metadata.Name),
+		DisplayVersion:    firstNonEmpty(input.Metadata.DisplayVersion, metadata.DisplayVersion),
+		ExternalVersionID: firstNonEmpty(input.Metadata.ExternalVersionID, metadata.ExternalVersionID),
+		Platform:          firstNonEmpty(input.Metadata.Platform, metadata.Platform),
+		Account:           firstNonEmpty(input.Metadata.Account, metadata.Account),
+		DownloadedAt:      input.Metadata.DownloadedAt,
+	}
+
+	if entry.AppID == 0 {
+		entry.AppID = metadata.AppID
+	}
+
+	if entry.DownloadedAt.IsZero() {
+		entry.DownloadedAt = info.ModTime()
+	}
+
+	entry.DownloadedAt = entry.DownloadedAt.UTC().Truncate(time.Second)
+
+	// The package is stored before the index is locked, so that adding large packages does not block
+	// other processes. Identical packages are stored at the same path, so concurrent adds are harmless.
+	err = l.store(input.Path, hash, input.Move)
+	if err != nil {
+		return AddOutput{}, err
+	}
+
+	output := AddOutput{Package: entry}
+
+	err = l.update(func(entries []Package) ([]Package, error) {
+		for _, existing := range entries {
+			if existing.Hash == hash {
+				output = AddOutput{Package: existing, Duplicate: true}
+
+				return nil, nil
+			}
+		}
+
+		return append(entries, entry), nil
+	})
+	if err != nil {
+		return AddOutput{}, err
+	}
+
+	return output, nil
+}
+
+// store copies or moves the app package to its path in the library, unless it is already stored there.
+func (l *library) store(src, hash string, move bool) error {
+	dst := l.objectPath(hash)
+
+	_, err := os.Stat(dst)
+	if err == nil {
+		if move {
+			return removeFile(src)
+		}
+
+		return nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(dst), 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create library directory: %w", err)
+	}
+
+	if move && os.Rename(src, dst) == nil {
+		return nil
+	}
+
+	// The package is copied to a temporary file first, so that an interrupted copy never leaves a
+	// partial package at its path. Every writer uses its own temporary file, so that concurrent adds
+	// of the same package do not write to the same file.
+	tmp, err := os.CreateTemp(filepath.Dir(dst), filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	err = copyFile(src, tmp)
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return err
+	}
+
+	err = os.Rename(tmp.Name(), dst)
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return fmt.Errorf("failed to store app package: %w", err)
+	}
+
+	if move {
+		return removeFile(src)
+	}
+
+	return nil
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open app package: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash app package: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// copyFile copies the app package to the destination file and closes it.
+func copyFile(src string, dstFile *os.File) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		_ = dstFile.Close()
+
+		return fmt.Errorf("failed to open app package: %w", err)
+	}
+	defer srcFile.Close()
+
+	// Temporary files are created with mode 0600, but stored packages keep the mode of regular files.
+	err = dstFile.Chmod(0644)
+	if err == nil {
+		_, err = io.Copy(dstFile, srcFile)
+	}
+
+	closeErr := dstFile.Close()
+
+	if err != nil {
+		return fmt.Errorf("failed to copy app package: %w", err)
+	}
+
+	if closeErr != nil {
+		return fmt.Errorf("failed to copy app package: %w", closeErr)
+	}
+
+	return nil
+}
+
+func removeFile(path string) error {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove app package: %w", err)
+	}
+
+	return nil
+}
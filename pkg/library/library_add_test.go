@@ -0,0 +1,158 @@
+package library
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"howett.net/plist"
+)
+
+// createPackage writes an app package with the specified iTunesMetadata.plist fields.
+func createPackage(path string, metadata map[string]interface{}) {
+	file, err := os.Create(path)
+	Expect(err).ToNot(HaveOccurred())
+
+	writer := zip.NewWriter(file)
+
+	info, err := writer.Create("Payload/App.app/Info.plist")
+	Expect(err).ToNot(HaveOccurred())
+
+	data, err := plist.Marshal(map[string]interface{}{
+		"CFBundleIdentifier":         "app.bundle.id",
+		"CFBundleShortVersionString": "0.9",
+		"CFBundleSupportedPlatforms": []string{"iPhoneOS"},
+	}, plist.BinaryFormat)
+	Expect(err).ToNot(HaveOccurred())
+
+	_, err = info.Write(data)
+	Expect(err).ToNot(HaveOccurred())
+
+	if metadata != nil {
+		metadataFile, err := writer.Create("iTunesMetadata.plist")
+		Expect(err).ToNot(HaveOccurred())
+
+		data, err := plist.Marshal(metadata, plist.BinaryFormat)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = metadataFile.Write(data)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	Expect(writer.Close()).To(Succeed())
+	Expect(file.Close()).To(Succeed())
+}
+
+var _ = Describe("Library (Add)", func() {
+	var (
+		dir string
+		lib Library
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		lib = New(Args{Root: filepath.Join(dir, "library")})
+	})
+
+	When("the package was downloaded by the tool", func() {
+		var path string
+
+		BeforeEach(func() {
+			path = filepath.Join(dir, "app.ipa")
+			createPackage(path, map[string]interface{}{
+				"softwareVersionBundleId":           "app.bundle.id",
+				"itemId":                            1234,
+				"itemName":                          "App",
+				"bundleShortVersionString":          "1.0",
+				"softwareVersionExternalIdentifier": 5678,
+				"apple-id":                          "user@example.com",
+			})
+		})
+
+		It("stores the package under its hash with the metadata of the package", func() {
+			out, err := lib.Add(AddInput{Path: path, Metadata: Metadata{Platform: "iphone"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out.Duplicate).To(BeFalse())
+			Expect(out.Package.Hash).To(HaveLen(64))
+			Expect(out.Package.BundleID).To(Equal("app.bundle.id"))
+			Expect(out.Package.AppID).To(Equal(int64(1234)))
+			Expect(out.Package.Name).To(Equal("App"))
+			Expect(out.Package.DisplayVersion).To(Equal("1.0"))
+			Expect(out.Package.ExternalVersionID).To(Equal("5678"))
+			Expect(out.Package.Platform).To(Equal("iphone"))
+			Expect(out.Package.Account).To(Equal("user@example.com"))
+			Expect(out.Package.DownloadedAt).ToNot(BeZero())
+
+			stored, err := lib.Path(out.Package.Hash)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored).To(Equal(filepath.Join(dir, "library", "objects", out.Package.Hash[:2], out.Package.Hash+".ipa")))
+			Expect(path).To(BeAnExistingFile())
+		})
+
+		It("deduplicates identical packages", func() {
+			first, err := lib.Add(AddInput{Path: path})
+			Expect(err).ToNot(HaveOccurred())
+
+			copied := filepath.Join(dir, "copy.ipa")
+			data, err := os.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(os.WriteFile(copied, data, 0600)).To(Succeed())
+
+			second, err := lib.Add(AddInput{Path: copied, Move: true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(second.Duplicate).To(BeTrue())
+			Expect(second.Package).To(Equal(first.Package))
+			Expect(copied).ToNot(BeAnExistingFile())
+
+			entries, err := lib.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+		})
+
+		It("moves the package", func() {
+			out, err := lib.Add(AddInput{Path: path, Move: true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(path).ToNot(BeAnExistingFile())
+
+			_, err = lib.Path(out.Package.Hash)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	When("the package has no iTunesMetadata.plist", func() {
+		var path string
+
+		BeforeEach(func() {
+			path = filepath.Join(dir, "app.ipa")
+			createPackage(path, nil)
+		})
+
+		It("reads the metadata from the Info.plist", func() {
+			downloadedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+			out, err := lib.Add(AddInput{Path: path, Metadata: Metadata{AppID: 42, DownloadedAt: downloadedAt}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out.Package.BundleID).To(Equal("app.bundle.id"))
+			Expect(out.Package.DisplayVersion).To(Equal("0.9"))
+			Expect(out.Package.AppID).To(Equal(int64(42)))
+			Expect(out.Package.DownloadedAt).To(Equal(downloadedAt))
+		})
+	})
+
+	When("the file is not an app package", func() {
+		var path string
+
+		BeforeEach(func() {
+			path = filepath.Join(dir, "app.ipa")
+			Expect(os.WriteFile(path, []byte("not a zip"), 0600)).To(Succeed())
+		})
+
+		It("returns error", func() {
+			_, err := lib.Add(AddInput{Path: path})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
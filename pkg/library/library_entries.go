@@ -0,0 +1,73 @@
+package library
+
+import (
+	"fmt"
+	"os"
+)
+
+func (l *library) List() ([]Package, error) {
+	return l.read()
+}
+
+func (l *library) Find(query Query) ([]Package, error) {
+	entries, err := l.read()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []Package{}
+
+	for _, entry := range entries {
+		if query.matches(entry) {
+			result = append(result, entry)
+		}
+	}
+
+	return result, nil
+}
+
+func (l *library) Remove(hash string) (Package, error) {
+	var removed Package
+
+	err := l.update(func(entries []Package) ([]Package, error) {
+		index, err := resolve(entries, hash)
+		if err != nil {
+			return nil, err
+		}
+
+		removed = entries[index]
+
+		err = removeFile(l.objectPath(removed.Hash))
+		if err != nil {
+			return nil, err
+		}
+
+		return append(entries[:index], entries[index+1:]...), nil
+	})
+	if err != nil {
+		return Package{}, err
+	}
+
+	return removed, nil
+}
+
+func (l *library) Path(hash string) (string, error) {
+	entries, err := l.read()
+	if err != nil {
+		return "", err
+	}
+
+	index, err := resolve(entries, hash)
+	if err != nil {
+		return "", err
+	}
+
+	path := l.objectPath(entries[index].Hash)
+
+	_, err = os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read app package metadata: %w", err)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,101 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Library (Entries)", func() {
+	var (
+		dir     string
+		lib     Library
+		entries []Package
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		lib = New(Args{Root: filepath.Join(dir, "library")})
+		entries = nil
+
+		for _, version := range []string{"1.0", "2.0"} {
+			path := filepath.Join(dir, version+".ipa")
+			createPackage(path, map[string]interface{}{
+				"softwareVersionBundleId":  "app.bundle.id",
+				"bundleShortVersionString": version,
+			})
+
+			out, err := lib.Add(AddInput{Path: path})
+			Expect(err).ToNot(HaveOccurred())
+
+			entries = append(entries, out.Package)
+		}
+	})
+
+	It("lists the entries in the order they were added", func() {
+		result, err := lib.List()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(entries))
+	})
+
+	DescribeTable("finds the entries matching the query",
+		func(query Query, expected []int) {
+			result, err := lib.Find(query)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(len(expected)))
+
+			for i, index := range expected {
+				Expect(result[i]).To(Equal(entries[index]))
+			}
+		},
+		Entry("by bundle identifier", Query{BundleID: "app.bundle.id"}, []int{0, 1}),
+		Entry("by display version", Query{BundleID: "app.bundle.id", DisplayVersion: "2.0"}, []int{1}),
+		Entry("without match", Query{BundleID: "other.bundle.id"}, []int{}),
+	)
+
+	When("removing an entry by hash prefix", func() {
+		It("removes the package", func() {
+			path, err := lib.Path(entries[0].Hash[:8])
+			Expect(err).ToNot(HaveOccurred())
+
+			removed, err := lib.Remove(entries[0].Hash[:8])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(Equal(entries[0]))
+			Expect(path).ToNot(BeAnExistingFile())
+
+			result, err := lib.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(entries[1:]))
+		})
+	})
+
+	When("the hash does not match an entry", func() {
+		It("returns error", func() {
+			_, err := lib.Path("ffffffff")
+			Expect(err).To(MatchError(ErrPackageNotFound))
+
+			_, err = lib.Remove("ffffffff")
+			Expect(err).To(MatchError(ErrPackageNotFound))
+		})
+	})
+
+	When("the hash prefix is too short", func() {
+		It("returns error", func() {
+			_, err := lib.Path("ab")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("the index is invalid", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(filepath.Join(dir, "library", "index.json"), []byte("{"), 0600)).To(Succeed())
+		})
+
+		It("returns error", func() {
+			_, err := lib.List()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
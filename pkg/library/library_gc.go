@@ -0,0 +1,236 @@
+package library
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy describes the packages kept by the garbage collection of the library. A package is kept when
+// any of the rules of the policy keeps it.
+type Policy struct {
+	// KeepLatest keeps the packages of the latest versions of each app and platform. Zero disables the rule.
+	KeepLatest int
+	// KeepWithin keeps the packages downloaded within the duration. Zero disables the rule.
+	KeepWithin time.Duration
+}
+
+// ParsePolicy parses a comma-separated list of rules, e.g. "keep-latest=5,keep-within=90d". Durations
+// are Go durations, e.g. "36h", or a number of days or weeks, e.g. "90d" or "12w".
+func ParsePolicy(value string) (Policy, error) {
+	var policy Policy
+
+	for _, rule := range strings.Split(value, ",") {
+		name, argument, ok := strings.Cut(strings.TrimSpace(rule), "=")
+		if !ok {
+			return Policy{}, fmt.Errorf("invalid rule %q; rules must be in the form <name>=<value>", rule)
+		}
+
+		switch name {
+		case "keep-latest":
+			count, err := strconv.Atoi(argument)
+			if err != nil || count < 1 {
+				return Policy{}, fmt.Errorf("invalid number of versions %q for rule %q", argument, name)
+			}
+
+			policy.KeepLatest = count
+		case "keep-within":
+			duration, err := parseDuration(argument)
+			if err != nil || duration <= 0 {
+				return Policy{}, fmt.Errorf("invalid duration %q for rule %q", argument, name)
+			}
+
+			policy.KeepWithin = duration
+		default:
+			return Policy{}, fmt.Errorf("unknown rule %q; supported rules are keep-latest and keep-within", name)
+		}
+	}
+
+	return policy, nil
+}
+
+func parseDuration(value string) (time.Duration, error) {
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+
+	for suffix, unit := range units {
+		if count, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.Atoi(count)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+			}
+
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+	}
+
+	return duration, nil
+}
+
+type GCInput struct {
+	Policy Policy
+	// DryRun reports the packages which would be removed without removing them.
+	DryRun bool
+	// Now is the time the durations of the policy are relative to. Defaults to the current time.
+	Now time.Time
+}
+
+type GCOutput struct {
+	Removed        []Package
+	Kept           int
+	ReclaimedBytes int64
+}
+
+func (l *library) GC(input GCInput) (GCOutput, error) {
+	if input.Policy == (Policy{}) {
+		return GCOutput{}, errors.New("the policy must contain at least one rule")
+	}
+
+	if input.Now.IsZero() {
+		input.Now = time.Now()
+	}
+
+	var (
+		output    = GCOutput{Removed: []Package{}}
+		removeErr error
+	)
+
+	err := l.update(func(entries []Package) ([]Package, error) {
+		keep := input.Policy.keep(entries, input.Now)
+		kept := []Package{}
+
+		for i, entry := range entries {
+			if keep[i] || removeErr != nil {
+				kept = append(kept, entry)
+
+				continue
+			}
+
+			if !input.DryRun {
+				// The packages removed so far are removed from the index even when a removal fails.
+				removeErr = removeFile(l.objectPath(entry.Hash))
+				if removeErr != nil {
+					kept = append(kept, entry)
+
+					continue
+				}
+			}
+
+			output.Removed = append(output.Removed, entry)
+			output.ReclaimedBytes += entry.Size
+		}
+
+		output.Kept = len(kept)
+
+		if input.DryRun || len(output.Removed) == 0 {
+			return nil, nil
+		}
+
+		return kept, nil
+	})
+	if err != nil {
+		return GCOutput{}, err
+	}
+
+	if removeErr != nil {
+		return GCOutput{}, removeErr
+	}
+
+	return output, nil
+}
+
+// keep returns whether the policy keeps each of the packages.
+func (p Policy) keep(entries []Package, now time.Time) []bool {
+	result := make([]bool, len(entries))
+
+	if p.KeepWithin > 0 {
+		for i, entry := range entries {
+			if now.Sub(entry.DownloadedAt) <= p.KeepWithin {
+				result[i] = true
+			}
+		}
+	}
+
+	if p.KeepLatest > 0 {
+		for _, indices := range groupByAppPlatform(entries) {
+			for _, i := range latestVersions(entries, indices, p.KeepLatest) {
+				result[i] = true
+			}
+		}
+	}
+
+	return result
+}
+
+// groupByAppPlatform returns the indices of the packages of each app and platform, so that the versions
+// of an app for different platforms do not compete with each other.
+func groupByAppPlatform(entries []Package) map[string][]int {
+	groups := map[string][]int{}
+
+	for i, entry := range entries {
+		key := entry.BundleID
+		if entry.AppID != 0 {
+			key = strconv.FormatInt(entry.AppID, 10)
+		}
+
+		key += "/" + entry.Platform
+
+		groups[key] = append(groups[key], i)
+	}
+
+	return groups
+}
+
+// latestVersions returns the indices of the packages of the latest count versions among the specified
+// packages. Versions are ordered by external version identifier, which increases with every release, and
+// then by download time. Packages whose identifier is not numeric are ordered after the others.
+func latestVersions(entries []Package, indices []int, count int) []int {
+	sorted := append([]int{}, indices...)
+
+	sort.SliceStable(sorted, func(a, b int) bool {
+		x, y := entries[sorted[a]], entries[sorted[b]]
+
+		xID, xErr := strconv.ParseInt(x.ExternalVersionID, 10, 64)
+		yID, yErr := strconv.ParseInt(y.ExternalVersionID, 10, 64)
+
+		switch {
+		case (xErr == nil) != (yErr == nil):
+			return xErr == nil
+		case xErr == nil && xID != yID:
+			return xID > yID
+		default:
+			return x.DownloadedAt.After(y.DownloadedAt)
+		}
+	})
+
+	var (
+		result   []int
+		versions = map[string]bool{}
+	)
+
+	for _, i := range sorted {
+		version := firstNonEmpty(entries[i].ExternalVersionID, entries[i].DisplayVersion, entries[i].Hash)
+
+		if !versions[version] {
+			if len(versions) == count {
+				continue
+			}
+
+			versions[version] = true
+		}
+
+		result = append(result, i)
+	}
+
+	return result
+}
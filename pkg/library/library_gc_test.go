@@ -0,0 +1,139 @@
+package library
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Library (GC)", func() {
+	var (
+		dir      string
+		lib      Library
+		now      time.Time
+		packages []Package
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		lib = New(Args{Root: filepath.Join(dir, "library")})
+		now = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+		packages = nil
+
+		// Versions 1 to 4 of an app, downloaded 40, 30, 20 and 10 days ago, and a single version of another app
+		// downloaded 100 days ago.
+		for i, app := range []string{"app.one", "app.one", "app.one", "app.one", "app.two"} {
+			path := filepath.Join(dir, fmt.Sprintf("%d.ipa", i))
+			createPackage(path, map[string]interface{}{
+				"softwareVersionBundleId":           app,
+				"softwareVersionExternalIdentifier": 100 + i,
+			})
+
+			age := time.Duration(40-i*10) * 24 * time.Hour
+			if app == "app.two" {
+				age = 100 * 24 * time.Hour
+			}
+
+			out, err := lib.Add(AddInput{Path: path, Metadata: Metadata{DownloadedAt: now.Add(-age)}})
+			Expect(err).ToNot(HaveOccurred())
+
+			packages = append(packages, out.Package)
+		}
+	})
+
+	DescribeTable("removes the packages not kept by the policy",
+		func(policy string, removed []int) {
+			parsed, err := ParsePolicy(policy)
+			Expect(err).ToNot(HaveOccurred())
+
+			out, err := lib.GC(GCInput{Policy: parsed, Now: now})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out.Removed).To(HaveLen(len(removed)))
+			Expect(out.Kept).To(Equal(len(packages) - len(removed)))
+
+			var reclaimed int64
+
+			for i, index := range removed {
+				Expect(out.Removed[i]).To(Equal(packages[index]))
+				Expect(filepath.Join(dir, "library", "objects", packages[index].Hash[:2], packages[index].Hash+".ipa")).ToNot(BeAnExistingFile())
+
+				reclaimed += packages[index].Size
+			}
+
+			Expect(out.ReclaimedBytes).To(Equal(reclaimed))
+
+			entries, err := lib.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(out.Kept))
+		},
+		Entry("latest versions per app", "keep-latest=2", []int{0, 1}),
+		Entry("recent downloads", "keep-within=25d", []int{0, 1, 4}),
+		Entry("latest versions or recent downloads", "keep-latest=1,keep-within=35d", []int{0}),
+		Entry("weeks", "keep-within=2w", []int{0, 1, 2, 4}),
+	)
+
+	When("running dry", func() {
+		It("does not remove the packages", func() {
+			out, err := lib.GC(GCInput{Policy: Policy{KeepLatest: 1}, DryRun: true, Now: now})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out.Removed).To(HaveLen(3))
+
+			entries, err := lib.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(Equal(packages))
+
+			_, err = lib.Path(packages[0].Hash)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	When("the policy is empty", func() {
+		It("returns error", func() {
+			_, err := lib.GC(GCInput{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	DescribeTable("rejects invalid policies",
+		func(policy string) {
+			_, err := ParsePolicy(policy)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("missing value", "keep-latest"),
+		Entry("invalid count", "keep-latest=0"),
+		Entry("invalid duration", "keep-within=soon"),
+		Entry("unknown rule", "keep-everything=1"),
+	)
+})
+
+var _ = Describe("Policy (keep)", func() {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time {
+		return now.Add(-time.Duration(days) * 24 * time.Hour)
+	}
+
+	It("keeps the latest versions of each platform of an app", func() {
+		entries := []Package{
+			{AppID: 1, Platform: "iphone", ExternalVersionID: "100", DownloadedAt: daysAgo(3)},
+			{AppID: 1, Platform: "iphone", ExternalVersionID: "101", DownloadedAt: daysAgo(2)},
+			{AppID: 1, Platform: "appletv", ExternalVersionID: "200", DownloadedAt: daysAgo(4)},
+		}
+
+		Expect(Policy{KeepLatest: 1}.keep(entries, now)).To(Equal([]bool{false, true, true}))
+	})
+
+	It("orders the versions without a numeric identifier after the others", func() {
+		entries := []Package{
+			{AppID: 1, ExternalVersionID: "100", DownloadedAt: daysAgo(3)},
+			{AppID: 1, DisplayVersion: "2.0", DownloadedAt: daysAgo(1)},
+			{AppID: 1, ExternalVersionID: "101", DownloadedAt: daysAgo(5)},
+			{AppID: 1, DisplayVersion: "1.0", DownloadedAt: daysAgo(6)},
+		}
+
+		Expect(Policy{KeepLatest: 2}.keep(entries, now)).To(Equal([]bool{true, false, true, false}))
+		Expect(Policy{KeepLatest: 3}.keep(entries, now)).To(Equal([]bool{true, true, true, false}))
+	})
+})
@@ -0,0 +1,141 @@
+package library
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/majd/ipatool/v2/pkg/util"
+	"github.com/majd/ipatool/v2/pkg/util/filelock"
+)
+
+const (
+	indexFileName     = "index.json"
+	objectsDirName    = "objects"
+	packageExtension  = ".ipa"
+	minimumHashPrefix = 4
+)
+
+type indexFile struct {
+	Entries []Package `json:"entries"`
+}
+
+// objectPath returns the path the app package with the specified hash is stored at.
+func (l *library) objectPath(hash string) string {
+	return filepath.Join(l.root, objectsDirName, hash[:2], hash+packageExtension)
+}
+
+// read returns the entries of the index while holding its lock.
+func (l *library) read() ([]Package, error) {
+	var result []Package
+
+	err := l.update(func(entries []Package) ([]Package, error) {
+		result = entries
+
+		return nil, nil
+	})
+
+	return result, err
+}
+
+// update replaces the entries of the index with the entries returned by fn while holding its lock. The
+// index is left unchanged when fn returns nil entries.
+func (l *library) update(fn func(entries []Package) ([]Package, error)) error {
+	err := os.MkdirAll(l.root, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create library directory: %w", err)
+	}
+
+	lock, err := filelock.Lock(filepath.Join(l.root, indexFileName+".lock"), true)
+	if err != nil {
+		return fmt.Errorf("failed to lock library index: %w", err)
+	}
+	defer filelock.Unlock(lock)
+
+	entries, err := l.load()
+	if err != nil {
+		return err
+	}
+
+	updated, err := fn(entries)
+	if err != nil {
+		return err
+	}
+
+	if updated == nil {
+		return nil
+	}
+
+	return l.save(updated)
+}
+
+func (l *library) load() ([]Package, error) {
+	data, err := os.ReadFile(filepath.Join(l.root, indexFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return []Package{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read library index: %w", err)
+	}
+
+	var index indexFile
+
+	err = json.Unmarshal(data, &index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse library index: %w", err)
+	}
+
+	if index.Entries == nil {
+		index.Entries = []Package{}
+	}
+
+	return index.Entries, nil
+}
+
+// save writes the index atomically, so that the index is never left partially written.
+func (l *library) save(entries []Package) error {
+	data, err := json.MarshalIndent(indexFile{Entries: entries}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal library index: %w", err)
+	}
+
+	err = util.WriteFileAtomic(filepath.Join(l.root, indexFileName), data, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write library index: %w", err)
+	}
+
+	return nil
+}
+
+// resolve returns the index of the entry whose hash starts with the prefix.
+func resolve(entries []Package, prefix string) (int, error) {
+	prefix = strings.ToLower(prefix)
+
+	if len(prefix) < minimumHashPrefix {
+		return -1, fmt.Errorf("hash prefix %q must be at least %d characters long", prefix, minimumHashPrefix)
+	}
+
+	index := -1
+
+	for i, entry := range entries {
+		if !strings.HasPrefix(entry.Hash, prefix) {
+			continue
+		}
+
+		if index != -1 {
+			return -1, fmt.Errorf("%w: %s", ErrAmbiguousHash, prefix)
+		}
+
+		index = i
+	}
+
+	if index == -1 {
+		return -1, fmt.Errorf("%w: %s", ErrPackageNotFound, prefix)
+	}
+
+	return index, nil
+}
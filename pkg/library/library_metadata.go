@@ -0,0 +1,128 @@
+package library
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"howett.net/plist"
+)
+
+// packageMetadata holds the fields of the iTunesMetadata.plist file written by the download command.
+type packageMetadata struct {
+	BundleID          string      `plist:"softwareVersionBundleId,omitempty"`
+	AppID             interface{} `plist:"itemId,omitempty"`
+	Name              string      `plist:"itemName,omitempty"`
+	DisplayVersion    string      `plist:"bundleShortVersionString,omitempty"`
+	ExternalVersionID interface{} `plist:"softwareVersionExternalIdentifier,omitempty"`
+	Account           string      `plist:"apple-id,omitempty"`
+}
+
+type packageInfo struct {
+	BundleID           string   `plist:"CFBundleIdentifier,omitempty"`
+	Name               string   `plist:"CFBundleDisplayName,omitempty"`
+	DisplayVersion     string   `plist:"CFBundleShortVersionString,omitempty"`
+	SupportedPlatforms []string `plist:"CFBundleSupportedPlatforms,omitempty"`
+}
+
+// readMetadata reads the metadata of the app package from its iTunesMetadata.plist file and, for the
+// fields it lacks, from the Info.plist file of the app.
+func readMetadata(path string) (Metadata, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("failed to open app package: %w", err)
+	}
+	defer reader.Close()
+
+	var (
+		metadata packageMetadata
+		info     packageInfo
+	)
+
+	for _, file := range reader.File {
+		switch {
+		case file.Name == "iTunesMetadata.plist":
+			err = decodePlist(file, &metadata)
+		case isAppInfoPlist(file.Name):
+			err = decodePlist(file, &info)
+		}
+
+		if err != nil {
+			return Metadata{}, err
+		}
+	}
+
+	result := Metadata{
+		BundleID:          firstNonEmpty(metadata.BundleID, info.BundleID),
+		AppID:             parseInt64(metadata.AppID),
+		Name:              firstNonEmpty(metadata.Name, info.Name),
+		DisplayVersion:    firstNonEmpty(metadata.DisplayVersion, info.DisplayVersion),
+		ExternalVersionID: formatID(metadata.ExternalVersionID),
+		Account:           metadata.Account,
+	}
+
+	for _, platform := range info.SupportedPlatforms {
+		if platform == "AppleTVOS" {
+			result.Platform = "appletv"
+		}
+	}
+
+	return result, nil
+}
+
+// isAppInfoPlist returns true for the Info.plist file of the main app, e.g. Payload/App.app/Info.plist.
+func isAppInfoPlist(name string) bool {
+	parts := strings.Split(name, "/")
+
+	return len(parts) == 3 && parts[0] == "Payload" && strings.HasSuffix(parts[1], ".app") && parts[2] == "Info.plist"
+}
+
+func decodePlist(file *zip.File, v interface{}) error {
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", file.Name, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file.Name, err)
+	}
+
+	_, err = plist.Unmarshal(data, v)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", file.Name, err)
+	}
+
+	return nil
+}
+
+// formatID formats an identifier stored either as a number or as a string.
+func formatID(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func parseInt64(value interface{}) int64 {
+	result, _ := strconv.ParseInt(formatID(value), 10, 64)
+
+	return result
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,62 @@
+package log
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/majd/ipatool/v2/pkg/schema"
+	"github.com/rs/zerolog"
+)
+
+// Fields adds the exported fields of the specified struct to the event, in declaration order and
+// named after their json tag. Fields tagged with omitempty are skipped when empty. Values
+// implementing the zerolog marshaler interfaces are added with them.
+func Fields(event *zerolog.Event, v interface{}) *zerolog.Event {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		return event
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitEmpty, ok := schema.FieldName(field)
+		if !ok || (omitEmpty && value.Field(i).IsZero()) {
+			continue
+		}
+
+		event = addField(event, name, value.Field(i).Interface())
+	}
+
+	return event
+}
+
+func addField(event *zerolog.Event, name string, value interface{}) *zerolog.Event {
+	switch v := value.(type) {
+	case zerolog.LogArrayMarshaler:
+		return event.Array(name, v)
+	case zerolog.LogObjectMarshaler:
+		return event.Object(name, v)
+	case time.Time:
+		return event.Time(name, v)
+	case string:
+		return event.Str(name, v)
+	case bool:
+		return event.Bool(name, v)
+	case int:
+		return event.Int(name, v)
+	case int64:
+		return event.Int64(name, v)
+	case float64:
+		return event.Float64(name, v)
+	case []string:
+		return event.Strs(name, v)
+	case []int64:
+		return event.Ints64(name, v)
+	default:
+		return event.Interface(name, v)
+	}
+}
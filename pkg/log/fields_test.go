@@ -0,0 +1,69 @@
+package log
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+type testObject struct{}
+
+func (testObject) MarshalZerologObject(event *zerolog.Event) {
+	event.Str("custom", "value")
+}
+
+type testFields struct {
+	Name      string            `json:"name"`
+	Count     int               `json:"count"`
+	ID        int64             `json:"id"`
+	Price     float64           `json:"price,omitempty"`
+	Enabled   bool              `json:"enabled"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Keys      []string          `json:"keys"`
+	Object    testObject        `json:"object"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Ignored   string            `json:"-"`
+	hidden    string
+}
+
+var _ = Describe("Fields", func() {
+	var (
+		buf    *bytes.Buffer
+		logger zerolog.Logger
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		logger = zerolog.New(buf)
+	})
+
+	It("adds the fields of the struct in order", func() {
+		Fields(logger.Info(), testFields{
+			Name:      "name",
+			Count:     1,
+			ID:        2,
+			Enabled:   true,
+			CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Keys:      []string{"a"},
+			Labels:    map[string]string{"b": "c"},
+			Ignored:   "ignored",
+			hidden:    "hidden",
+		}).Send()
+
+		Expect(buf.String()).To(Equal(`{"level":"info","name":"name","count":1,"id":2,"enabled":true,` +
+			`"createdAt":"2024-01-02T03:04:05Z","keys":["a"],"object":{"custom":"value"},"labels":{"b":"c"}}` + "\n"))
+	})
+
+	It("accepts pointers", func() {
+		Fields(logger.Info(), &testFields{Name: "name"}).Send()
+		Expect(buf.String()).To(ContainSubstring(`"name":"name"`))
+	})
+
+	It("ignores values which are not structs", func() {
+		Fields(logger.Info(), "value").Send()
+		Expect(buf.String()).To(Equal(`{"level":"info"}` + "\n"))
+	})
+})
@@ -0,0 +1,10 @@
+package log
+
+import (
+	"io"
+)
+
+// Formatter renders the result of a command, decoded from a log event, in a specific output format.
+type Formatter interface {
+	Format(w io.Writer, record Record) error
+}
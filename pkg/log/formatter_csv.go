@@ -0,0 +1,53 @@
+package log
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+type csvFormatter struct{}
+
+// NewCSVFormatter returns a formatter rendering the primary collection of a result as CSV with a
+// header row. Results without a collection are rendered as a single row.
+func NewCSVFormatter() Formatter {
+	return &csvFormatter{}
+}
+
+func (f *csvFormatter) Format(w io.Writer, record Record) error {
+	records := []Record{record.Without("success")}
+
+	if key, values, ok := record.collection(); ok {
+		records = rows(key, values)
+	}
+
+	keys := columns(records)
+	cw := csv.NewWriter(w)
+
+	err := cw.Write(keys)
+	if err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	for _, row := range records {
+		line := make([]string, len(keys))
+		for i, column := range keys {
+			value, _ := row.Get(column)
+			line[i] = cell(value)
+		}
+
+		err = cw.Write(line)
+		if err != nil {
+			return fmt.Errorf("failed to write row: %w", err)
+		}
+	}
+
+	cw.Flush()
+
+	err = cw.Error()
+	if err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+
+	return nil
+}
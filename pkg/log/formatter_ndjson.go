@@ -0,0 +1,35 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type ndjsonFormatter struct{}
+
+// NewNDJSONFormatter returns a formatter writing each element of the primary collection of a result
+// as a separate JSON line, so batch results can be processed as a stream. Results without a
+// collection are written as a single line.
+func NewNDJSONFormatter() Formatter {
+	return &ndjsonFormatter{}
+}
+
+func (f *ndjsonFormatter) Format(w io.Writer, record Record) error {
+	lines := []Record{record}
+
+	if key, values, ok := record.collection(); ok {
+		lines = rows(key, values)
+	}
+
+	encoder := json.NewEncoder(w)
+
+	for _, line := range lines {
+		err := encoder.Encode(line)
+		if err != nil {
+			return fmt.Errorf("failed to write line: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+type tableFormatter struct{}
+
+// NewTableFormatter returns a formatter rendering the primary collection of a result as an aligned
+// table, followed by its remaining fields. Results without a collection are rendered as a table of
+// fields and values.
+func NewTableFormatter() Formatter {
+	return &tableFormatter{}
+}
+
+func (f *tableFormatter) Format(w io.Writer, record Record) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	key, values, ok := record.collection()
+	if !ok {
+		for _, field := range record {
+			fmt.Fprintf(tw, "%s\t%s\n", field.Key, cell(field.Value))
+		}
+
+		return flush(tw)
+	}
+
+	records := rows(key, values)
+	keys := columns(records)
+
+	for i, column := range keys {
+		fmt.Fprint(tw, column)
+
+		if i < len(keys)-1 {
+			fmt.Fprint(tw, "\t")
+		}
+	}
+
+	fmt.Fprintln(tw)
+
+	for _, row := range records {
+		for i, column := range keys {
+			value, _ := row.Get(column)
+			fmt.Fprint(tw, cell(value))
+
+			if i < len(keys)-1 {
+				fmt.Fprint(tw, "\t")
+			}
+		}
+
+		fmt.Fprintln(tw)
+	}
+
+	err := flush(tw)
+	if err != nil {
+		return err
+	}
+
+	remaining := record.Without(key, "success")
+	if len(remaining) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(w)
+
+	for _, field := range remaining {
+		fmt.Fprintf(tw, "%s:\t%s\n", field.Key, cell(field.Value))
+	}
+
+	return flush(tw)
+}
+
+func flush(tw *tabwriter.Writer) error {
+	err := tw.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write table: %w", err)
+	}
+
+	return nil
+}
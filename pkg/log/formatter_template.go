@@ -0,0 +1,143 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+type templateFormatter struct {
+	template *template.Template
+}
+
+// NewTemplateFormatter returns a formatter rendering each result through the specified Go template.
+// The fields of the result are accessible by name, e.g. `{{range .apps}}{{.bundleID}}{{"\n"}}{{end}}`.
+func NewTemplateFormatter(text string) (Formatter, error) {
+	tmpl, err := template.New("format").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	return &templateFormatter{template: tmpl}, nil
+}
+
+func (f *templateFormatter) Format(w io.Writer, record Record) error {
+	err := f.template.Execute(w, templateData(record))
+	if err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return nil
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode value: %w", err)
+		}
+
+		return string(data), nil
+	},
+	"join": func(sep string, values []interface{}) string {
+		parts := make([]string, len(values))
+		for i, value := range values {
+			parts[i] = fmt.Sprint(value)
+		}
+
+		return strings.Join(parts, sep)
+	},
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"trim":    strings.TrimSpace,
+	"replace": func(old, replacement, value string) string { return strings.ReplaceAll(value, old, replacement) },
+	"default": func(fallback, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return fallback
+		}
+
+		return value
+	},
+	"date": func(layout string, value string) (string, error) {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse date: %w", err)
+		}
+
+		return parsed.Format(layout), nil
+	},
+	"bytes": humanBytes,
+	// float converts integers to floats, e.g. to compare prices which are encoded as integers when whole.
+	"float": func(value interface{}) float64 {
+		switch v := value.(type) {
+		case int64:
+			return float64(v)
+		case float64:
+			return v
+		default:
+			return 0
+		}
+	},
+}
+
+// templateData converts a record into maps, slices and native numbers so templates can use the
+// built-in functions such as index, eq or gt on its fields.
+func templateData(value interface{}) interface{} {
+	switch v := value.(type) {
+	case Record:
+		data := make(map[string]interface{}, len(v))
+		for _, field := range v {
+			data[field.Key] = templateData(field.Value)
+		}
+
+		return data
+	case []interface{}:
+		data := make([]interface{}, len(v))
+		for i, item := range v {
+			data[i] = templateData(item)
+		}
+
+		return data
+	case json.Number:
+		if number, err := v.Int64(); err == nil {
+			return number
+		}
+
+		number, _ := v.Float64()
+
+		return number
+	default:
+		return v
+	}
+}
+
+// humanBytes formats a number of bytes using binary units, e.g. 1.5 MiB.
+func humanBytes(value interface{}) string {
+	var size float64
+
+	switch v := value.(type) {
+	case int64:
+		size = float64(v)
+	case float64:
+		size = v
+	case string:
+		_, _ = fmt.Sscan(v, &size)
+	}
+
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	unit := 0
+
+	for size >= 1024 && unit < len(units)-1 {
+		size /= 1024
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%.0f %s", size, units[unit])
+	}
+
+	return fmt.Sprintf("%.1f %s", size, units[unit])
+}
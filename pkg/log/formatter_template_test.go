@@ -0,0 +1,63 @@
+package log
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Formatter (Template)", func() {
+	var (
+		record Record
+		buf    *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		record, err = ParseRecord([]byte(`{"count":2,"apps":[` +
+			`{"bundleID":"com.example.first","version":"1.0","price":0,"fileSizeBytes":"1572864","genres":["Games","Puzzle"]},` +
+			`{"bundleID":"com.example.second","version":"2.1","price":1.99,"releaseDate":"2024-03-01T10:00:00Z"}` +
+			`],"success":true}`))
+		Expect(err).ToNot(HaveOccurred())
+
+		buf = &bytes.Buffer{}
+	})
+
+	It("returns error for invalid templates", func() {
+		_, err := NewTemplateFormatter("{{.count")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns error when execution fails", func() {
+		formatter, err := NewTemplateFormatter(`{{index .apps 5}}`)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = formatter.Format(buf, record)
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("renders results",
+		func(text, expected string) {
+			formatter, err := NewTemplateFormatter(text)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = formatter.Format(buf, record)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).To(Equal(expected))
+		},
+		Entry("ranges over collections",
+			`{{range .apps}}{{.bundleID}} {{.version}}{{"\n"}}{{end}}`,
+			"com.example.first 1.0\ncom.example.second 2.1\n"),
+		Entry("compares numbers", `{{range .apps}}{{if gt (float .price) 0.0}}{{.bundleID}}{{end}}{{end}}`, "com.example.second"),
+		Entry("accesses scalars", `{{.count}} {{.success}}`, "2 true"),
+		Entry("joins values", `{{join ";" (index .apps 0).genres}}`, "Games;Puzzle"),
+		Entry("encodes JSON", `{{json (index .apps 0).genres}}`, `["Games","Puzzle"]`),
+		Entry("changes case", `{{upper (index .apps 0).bundleID}}`, "COM.EXAMPLE.FIRST"),
+		Entry("replaces text", `{{replace "." "/" (index .apps 0).bundleID}}`, "com/example/first"),
+		Entry("falls back to defaults", `{{default "n/a" (index .apps 1).fileSizeBytes}}`, "n/a"),
+		Entry("formats dates", `{{date "2006-01-02" (index .apps 1).releaseDate}}`, "2024-03-01"),
+		Entry("formats sizes", `{{bytes (index .apps 0).fileSizeBytes}}`, "1.5 MiB"),
+	)
+})
@@ -0,0 +1,127 @@
+package log
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Formatter", func() {
+	var (
+		collection Record
+		scalars    Record
+		buf        *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		collection, err = ParseRecord([]byte(`{"count":2,"apps":[{"id":1,"name":"First"},{"id":2,"name":"Second, Inc.","price":0.99}],"success":true}`))
+		Expect(err).ToNot(HaveOccurred())
+
+		scalars, err = ParseRecord([]byte(`{"output":"/tmp/app.ipa","versions":["1","2"],"purchased":false,"success":true}`))
+		Expect(err).ToNot(HaveOccurred())
+
+		buf = &bytes.Buffer{}
+	})
+
+	When("formatting as table", func() {
+		It("renders the collection followed by the remaining fields", func() {
+			err := NewTableFormatter().Format(buf, collection)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).To(Equal("" +
+				"id  name          price\n" +
+				"1   First         \n" +
+				"2   Second, Inc.  0.99\n" +
+				"\n" +
+				"count:  2\n"))
+		})
+
+		It("renders scalar collections in a single column", func() {
+			err := NewTableFormatter().Format(buf, scalars)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).To(Equal("" +
+				"versions\n" +
+				"1\n" +
+				"2\n" +
+				"\n" +
+				"output:     /tmp/app.ipa\n" +
+				"purchased:  false\n"))
+		})
+
+		It("renders fields and values when there is no collection", func() {
+			err := NewTableFormatter().Format(buf, scalars.Without("versions"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).To(Equal("" +
+				"output     /tmp/app.ipa\n" +
+				"purchased  false\n" +
+				"success    true\n"))
+		})
+	})
+
+	When("formatting as CSV", func() {
+		It("renders the collection with a header row", func() {
+			err := NewCSVFormatter().Format(buf, collection)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).To(Equal("" +
+				"id,name,price\n" +
+				"1,First,\n" +
+				"2,\"Second, Inc.\",0.99\n"))
+		})
+
+		It("renders a single row when there is no collection", func() {
+			err := NewCSVFormatter().Format(buf, scalars.Without("versions"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).To(Equal("" +
+				"output,purchased\n" +
+				"/tmp/app.ipa,false\n"))
+		})
+	})
+
+	When("formatting as YAML", func() {
+		It("renders each result as a document", func() {
+			formatter := NewYAMLFormatter()
+
+			err := formatter.Format(buf, collection)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = formatter.Format(buf, Record{{Key: "id", Value: "123"}})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(buf.String()).To(Equal("" +
+				"count: 2\n" +
+				"apps:\n" +
+				"  - id: 1\n" +
+				"    name: First\n" +
+				"  - id: 2\n" +
+				"    name: Second, Inc.\n" +
+				"    price: 0.99\n" +
+				"success: true\n" +
+				"---\n" +
+				"id: \"123\"\n"))
+		})
+	})
+
+	When("formatting as NDJSON", func() {
+		It("writes each element of the collection as a line", func() {
+			err := NewNDJSONFormatter().Format(buf, collection)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).To(Equal("" +
+				`{"id":1,"name":"First"}` + "\n" +
+				`{"id":2,"name":"Second, Inc.","price":0.99}` + "\n"))
+		})
+
+		It("wraps scalar elements in an object", func() {
+			err := NewNDJSONFormatter().Format(buf, scalars)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).To(Equal(`{"versions":"1"}` + "\n" + `{"versions":"2"}` + "\n"))
+		})
+
+		It("writes results without a collection as a single line", func() {
+			err := NewNDJSONFormatter().Format(buf, scalars.Without("versions"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).To(Equal(`{"output":"/tmp/app.ipa","purchased":false,"success":true}` + "\n"))
+		})
+	})
+})
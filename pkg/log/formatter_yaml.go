@@ -0,0 +1,99 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+type yamlFormatter struct {
+	documents int
+}
+
+// NewYAMLFormatter returns a formatter rendering each result as a YAML document.
+func NewYAMLFormatter() Formatter {
+	return &yamlFormatter{}
+}
+
+func (f *yamlFormatter) Format(w io.Writer, record Record) error {
+	node, err := yamlNode(record)
+	if err != nil {
+		return err
+	}
+
+	if f.documents > 0 {
+		_, err = io.WriteString(w, "---\n")
+		if err != nil {
+			return fmt.Errorf("failed to write document separator: %w", err)
+		}
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	err = encoder.Encode(node)
+	if err != nil {
+		return fmt.Errorf("failed to write document: %w", err)
+	}
+
+	f.documents++
+
+	err = encoder.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close encoder: %w", err)
+	}
+
+	return nil
+}
+
+func yamlNode(value interface{}) (*yaml.Node, error) {
+	switch v := value.(type) {
+	case Record:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+
+		for _, field := range v {
+			child, err := yamlNode(field.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: field.Key}, child)
+		}
+
+		return node, nil
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+
+		for _, item := range v {
+			child, err := yamlNode(item)
+			if err != nil {
+				return nil, err
+			}
+
+			node.Content = append(node.Content, child)
+		}
+
+		return node, nil
+	case json.Number:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: numberTag(v), Value: v.String()}, nil
+	default:
+		node := &yaml.Node{}
+
+		err := node.Encode(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode value: %w", err)
+		}
+
+		return node, nil
+	}
+}
+
+func numberTag(number json.Number) string {
+	if _, err := number.Int64(); err == nil {
+		return "!!int"
+	}
+
+	return "!!float"
+}
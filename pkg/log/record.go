@@ -0,0 +1,248 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Field is a single key-value pair of a record.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Record is a log event decoded from JSON which preserves the order of its fields. Values are
+// strings, json.Number, booleans, nil, nested records or []interface{}.
+type Record []Field
+
+// Get returns the value of the field with the specified key.
+func (r Record) Get(key string) (interface{}, bool) {
+	for _, field := range r {
+		if field.Key == key {
+			return field.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// Without returns a copy of the record without the fields with the specified keys.
+func (r Record) Without(keys ...string) Record {
+	result := Record{}
+
+	for _, field := range r {
+		if !containsKey(keys, field.Key) {
+			result = append(result, field)
+		}
+	}
+
+	return result
+}
+
+// MarshalJSON encodes the record preserving the order of its fields.
+func (r Record) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, field := range r {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(field.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode key: %w", err)
+		}
+
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode value: %w", err)
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// ParseRecord decodes a JSON object into a record.
+func ParseRecord(data []byte) (Record, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	value, err := decodeValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	record, ok := value.(Record)
+	if !ok {
+		return nil, errors.New("value is not an object")
+	}
+
+	return record, nil
+}
+
+func decodeValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token: %w", err)
+	}
+
+	switch token {
+	case json.Delim('{'):
+		record := Record{}
+
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read key: %w", err)
+			}
+
+			key, _ := keyToken.(string)
+
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			record = append(record, Field{Key: key, Value: value})
+		}
+
+		_, err = decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read token: %w", err)
+		}
+
+		return record, nil
+	case json.Delim('['):
+		values := []interface{}{}
+
+		for decoder.More() {
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, value)
+		}
+
+		_, err = decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read token: %w", err)
+		}
+
+		return values, nil
+	default:
+		return token, nil
+	}
+}
+
+// collection returns the key and the elements of the record's primary collection, which is the
+// first array of objects or, if there is none, the first array.
+func (r Record) collection() (string, []interface{}, bool) {
+	var (
+		fallbackKey    string
+		fallbackValues []interface{}
+		found          bool
+	)
+
+	for _, field := range r {
+		values, ok := field.Value.([]interface{})
+		if !ok {
+			continue
+		}
+
+		if len(values) > 0 {
+			if _, isRecord := values[0].(Record); isRecord {
+				return field.Key, values, true
+			}
+		}
+
+		if !found {
+			fallbackKey, fallbackValues, found = field.Key, values, true
+		}
+	}
+
+	return fallbackKey, fallbackValues, found
+}
+
+// rows converts the elements of a collection into records. Scalar elements are wrapped in a record
+// with a single field named after the collection.
+func rows(key string, values []interface{}) []Record {
+	result := make([]Record, len(values))
+
+	for i, value := range values {
+		if record, ok := value.(Record); ok {
+			result[i] = record
+		} else {
+			result[i] = Record{{Key: key, Value: value}}
+		}
+	}
+
+	return result
+}
+
+// columns returns the union of the keys of the records in order of first appearance.
+func columns(records []Record) []string {
+	var keys []string
+
+	for _, record := range records {
+		for _, field := range record {
+			if !containsKey(keys, field.Key) {
+				keys = append(keys, field.Key)
+			}
+		}
+	}
+
+	return keys
+}
+
+// cell renders a value as a single line of text.
+func cell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprintf("%t", v)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			if _, ok := item.(Record); ok {
+				data, _ := json.Marshal(v)
+
+				return string(data)
+			}
+
+			parts[i] = cell(item)
+		}
+
+		return strings.Join(parts, ",")
+	default:
+		data, _ := json.Marshal(v)
+
+		return string(data)
+	}
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Record", func() {
+	It("preserves the order of fields", func() {
+		record, err := ParseRecord([]byte(`{"b":1,"a":{"d":true,"c":null},"e":["x",2.5]}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(record).To(Equal(Record{
+			{Key: "b", Value: json.Number("1")},
+			{Key: "a", Value: Record{{Key: "d", Value: true}, {Key: "c", Value: nil}}},
+			{Key: "e", Value: []interface{}{"x", json.Number("2.5")}},
+		}))
+
+		data, err := json.Marshal(record)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal(`{"b":1,"a":{"d":true,"c":null},"e":["x",2.5]}`))
+	})
+
+	It("returns error for invalid JSON", func() {
+		_, err := ParseRecord([]byte(`{"a":`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns error when the value is not an object", func() {
+		_, err := ParseRecord([]byte(`[1]`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("removes fields", func() {
+		record := Record{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}, {Key: "c", Value: "3"}}
+		Expect(record.Without("a", "c")).To(Equal(Record{{Key: "b", Value: "2"}}))
+	})
+
+	When("the record has several arrays", func() {
+		It("prefers arrays of objects as the primary collection", func() {
+			record := Record{
+				{Key: "keys", Value: []interface{}{"a"}},
+				{Key: "apps", Value: []interface{}{Record{{Key: "id", Value: json.Number("1")}}}},
+			}
+
+			key, values, ok := record.collection()
+			Expect(ok).To(BeTrue())
+			Expect(key).To(Equal("apps"))
+			Expect(values).To(HaveLen(1))
+		})
+
+		It("falls back to the first array", func() {
+			record := Record{
+				{Key: "count", Value: json.Number("0")},
+				{Key: "keys", Value: []interface{}{"a"}},
+				{Key: "apps", Value: []interface{}{}},
+			}
+
+			key, _, ok := record.collection()
+			Expect(ok).To(BeTrue())
+			Expect(key).To(Equal("keys"))
+		})
+	})
+
+	DescribeTable("renders cells",
+		func(value interface{}, expected string) {
+			Expect(cell(value)).To(Equal(expected))
+		},
+		Entry("nil", nil, ""),
+		Entry("string", "text", "text"),
+		Entry("number", json.Number("1.5"), "1.5"),
+		Entry("boolean", true, "true"),
+		Entry("array of scalars", []interface{}{"a", json.Number("1")}, "a,1"),
+		Entry("array of objects", []interface{}{Record{{Key: "a", Value: "b"}}}, `[{"a":"b"}]`),
+		Entry("object", Record{{Key: "a", Value: "b"}}, `{"a":"b"}`),
+	)
+})
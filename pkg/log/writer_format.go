@@ -0,0 +1,87 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+type formatWriter struct {
+	formatter    Formatter
+	errFormatter Formatter
+	stdOutWriter io.Writer
+	stdErrWriter io.Writer
+	mu           sync.Mutex
+}
+
+type FormatWriterArgs struct {
+	Formatter Formatter
+	// Out receives the results of commands.
+	Out io.Writer
+	// Err receives errors, verbose logs and messages.
+	Err io.Writer
+	// ErrFormatter renders errors and verbose logs; defaults to Formatter.
+	ErrFormatter Formatter
+}
+
+// NewFormatWriter returns a writer which decodes the JSON events written by the logger and renders
+// them with the specified formatter. Events carrying a message, such as prompts and warnings, are
+// written as plain text.
+func NewFormatWriter(args FormatWriterArgs) Writer {
+	errFormatter := args.ErrFormatter
+	if errFormatter == nil {
+		errFormatter = args.Formatter
+	}
+
+	return &formatWriter{
+		formatter:    args.Formatter,
+		errFormatter: errFormatter,
+		stdOutWriter: args.Out,
+		stdErrWriter: args.Err,
+	}
+}
+
+func (w *formatWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.InfoLevel, p)
+}
+
+func (w *formatWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	record, err := ParseRecord(p)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse event: %w", err)
+	}
+
+	record = record.Without(zerolog.LevelFieldName, zerolog.TimestampFieldName)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := record.Get(zerolog.MessageFieldName); ok {
+		_, err = io.WriteString(w.stdErrWriter, message(record))
+	} else if level == zerolog.InfoLevel {
+		err = w.formatter.Format(w.stdOutWriter, record)
+	} else {
+		err = w.errFormatter.Format(w.stdErrWriter, record)
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to write data: %w", err)
+	}
+
+	return len(p), nil
+}
+
+// message renders an event carrying a message as a single line followed by its other fields.
+func message(record Record) string {
+	value, _ := record.Get(zerolog.MessageFieldName)
+	parts := []string{cell(value)}
+
+	for _, field := range record.Without(zerolog.MessageFieldName) {
+		parts = append(parts, fmt.Sprintf("%s=%s", field.Key, cell(field.Value)))
+	}
+
+	return strings.Join(parts, " ") + "\n"
+}
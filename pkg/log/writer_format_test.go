@@ -0,0 +1,66 @@
+package log
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("Writer (Format)", func() {
+	var (
+		stdout *bytes.Buffer
+		stderr *bytes.Buffer
+		sut    Writer
+	)
+
+	BeforeEach(func() {
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+		sut = NewFormatWriter(FormatWriterArgs{
+			Formatter: NewNDJSONFormatter(),
+			Out:       stdout,
+			Err:       stderr,
+		})
+	})
+
+	It("writes formatted results to stdout without log metadata", func() {
+		_, err := sut.WriteLevel(zerolog.InfoLevel, []byte(`{"level":"info","time":"now","output":"app.ipa"}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout.String()).To(Equal(`{"output":"app.ipa"}` + "\n"))
+		Expect(stderr.String()).To(BeEmpty())
+	})
+
+	It("writes formatted errors to stderr", func() {
+		_, err := sut.WriteLevel(zerolog.ErrorLevel, []byte(`{"level":"error","error":"failed","success":false}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout.String()).To(BeEmpty())
+		Expect(stderr.String()).To(Equal(`{"error":"failed","success":false}` + "\n"))
+	})
+
+	It("writes messages as plain text to stderr", func() {
+		_, err := sut.Write([]byte(`{"level":"info","countryCode":"US","message":"lookup failed"}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout.String()).To(BeEmpty())
+		Expect(stderr.String()).To(Equal("lookup failed countryCode=US\n"))
+	})
+
+	It("renders errors with the error formatter when specified", func() {
+		sut = NewFormatWriter(FormatWriterArgs{
+			Formatter:    NewNDJSONFormatter(),
+			Out:          stdout,
+			Err:          stderr,
+			ErrFormatter: NewCSVFormatter(),
+		})
+
+		_, err := sut.WriteLevel(zerolog.ErrorLevel, []byte(`{"level":"error","error":"failed","success":false}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stderr.String()).To(Equal("error\nfailed\n"))
+	})
+
+	It("returns error for invalid events", func() {
+		_, err := sut.Write([]byte(`invalid`))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,58 @@
+package progress
+
+// Phase is a step of a download.
+type Phase string
+
+const (
+	PhaseResolving   Phase = "resolving"
+	PhaseDownloading Phase = "downloading"
+	PhaseVerifying   Phase = "verifying"
+	PhasePatching    Phase = "patching"
+	PhaseSinf        Phase = "sinf"
+	PhaseDone        Phase = "done"
+)
+
+// Progress receives the progress of a download.
+//
+//go:generate go run go.uber.org/mock/mockgen -source=progress.go -destination=progress_mock.go -package progress
+type Progress interface {
+	// SetPhase reports that the download entered the phase.
+	SetPhase(phase Phase)
+	// SetTotal reports the size of the package in bytes, or zero when it is unknown.
+	SetTotal(total int64)
+	// SetDone reports the number of bytes of the package transferred so far, including the bytes of a
+	// resumed partial download.
+	SetDone(done int64)
+}
+
+// Discard is a progress which ignores the reports.
+var Discard Progress = discard{}
+
+type discard struct{}
+
+func (discard) SetPhase(Phase) {}
+
+func (discard) SetTotal(int64) {}
+
+func (discard) SetDone(int64) {}
+
+// Writer returns a writer reporting the number of bytes written to it, starting from the specified number
+// of bytes.
+func Writer(progress Progress, done int64) *CountingWriter {
+	return &CountingWriter{
+		progress: progress,
+		done:     done,
+	}
+}
+
+type CountingWriter struct {
+	progress Progress
+	done     int64
+}
+
+func (w *CountingWriter) Write(p []byte) (int, error) {
+	w.done += int64(len(p))
+	w.progress.SetDone(w.done)
+
+	return len(p), nil
+}
@@ -0,0 +1,38 @@
+package progress
+
+import (
+	"github.com/schollz/progressbar/v3"
+)
+
+type bar struct {
+	bar *progressbar.ProgressBar
+}
+
+// NewBar returns a progress rendered by the progress bar, which is described by the current phase.
+func NewBar(progressBar *progressbar.ProgressBar) Progress {
+	return &bar{bar: progressBar}
+}
+
+func (b *bar) SetPhase(phase Phase) {
+	if phase == PhaseDone {
+		// Finishing clears the bar when it is configured to, e.g. when the download was skipped.
+		_ = b.bar.Finish()
+
+		return
+	}
+
+	b.bar.Describe(string(phase))
+}
+
+func (b *bar) SetTotal(total int64) {
+	if total <= 0 {
+		// An unknown size is rendered as a spinner.
+		total = -1
+	}
+
+	b.bar.ChangeMax64(total)
+}
+
+func (b *bar) SetDone(done int64) {
+	_ = b.bar.Set64(done)
+}
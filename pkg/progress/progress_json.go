@@ -0,0 +1,121 @@
+package progress
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Event is a progress report written as a line of JSON.
+type Event struct {
+	// ID identifies the download when several downloads report to the same writer, e.g. a job ID.
+	ID         string `json:"id,omitempty"`
+	Phase      Phase  `json:"phase"`
+	BytesDone  int64  `json:"bytesDone"`
+	BytesTotal int64  `json:"bytesTotal,omitempty"`
+	// BytesPerSecond is the average rate of the transfer since it started.
+	BytesPerSecond float64 `json:"bytesPerSecond,omitempty"`
+	// ETA is the estimated number of seconds until the transfer completes.
+	ETA  float64   `json:"eta,omitempty"`
+	Time time.Time `json:"time"`
+}
+
+// JSONWriter writes the progress events of downloads as newline-delimited JSON. It is safe for use by
+// concurrent downloads.
+type JSONWriter struct {
+	writer   io.Writer
+	interval time.Duration
+	now      func() time.Time
+	mutex    sync.Mutex
+}
+
+type JSONWriterArgs struct {
+	Writer io.Writer
+	// Interval is the minimum time between the events of a transfer. Phase changes and the completion of
+	// the transfer are always reported. Defaults to one second.
+	Interval time.Duration
+}
+
+func NewJSONWriter(args JSONWriterArgs) *JSONWriter {
+	if args.Interval == 0 {
+		args.Interval = time.Second
+	}
+
+	return &JSONWriter{
+		writer:   args.Writer,
+		interval: args.Interval,
+		now:      time.Now,
+	}
+}
+
+// Progress returns the progress of a download, whose events carry the specified ID.
+func (w *JSONWriter) Progress(id string) Progress {
+	return &jsonProgress{
+		writer: w,
+		event:  Event{ID: id},
+	}
+}
+
+func (w *JSONWriter) write(event Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	// Progress is best effort, so that a closed output does not fail the download.
+	_, _ = w.writer.Write(append(data, '\n'))
+}
+
+type jsonProgress struct {
+	writer *JSONWriter
+	event  Event
+	// start and startDone are the time and the number of bytes of the first report of the transfer.
+	start     time.Time
+	startDone int64
+	last      time.Time
+}
+
+func (p *jsonProgress) SetPhase(phase Phase) {
+	p.event.Phase = phase
+	p.event.BytesPerSecond, p.event.ETA = 0, 0
+	p.start = time.Time{}
+	p.emit(p.writer.now())
+}
+
+func (p *jsonProgress) SetTotal(total int64) {
+	p.event.BytesTotal = total
+}
+
+func (p *jsonProgress) SetDone(done int64) {
+	now := p.writer.now()
+
+	if p.start.IsZero() {
+		p.start, p.startDone = now, done
+	}
+
+	p.event.BytesDone = done
+	p.event.BytesPerSecond, p.event.ETA = 0, 0
+
+	if elapsed := now.Sub(p.start).Seconds(); elapsed > 0 {
+		p.event.BytesPerSecond = float64(done-p.startDone) / elapsed
+	}
+
+	if p.event.BytesPerSecond > 0 && p.event.BytesTotal > done {
+		p.event.ETA = float64(p.event.BytesTotal-done) / p.event.BytesPerSecond
+	}
+
+	if now.Sub(p.last) >= p.writer.interval || (p.event.BytesTotal > 0 && done >= p.event.BytesTotal) {
+		p.emit(now)
+	}
+}
+
+func (p *jsonProgress) emit(now time.Time) {
+	p.last = now
+	p.event.Time = now
+
+	p.writer.write(p.event)
+}
@@ -0,0 +1,101 @@
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON Progress", func() {
+	var (
+		output *bytes.Buffer
+		now    time.Time
+		sut    Progress
+	)
+
+	events := func() []Event {
+		var result []Event
+
+		for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+			var event Event
+			Expect(json.Unmarshal([]byte(line), &event)).To(Succeed())
+
+			result = append(result, event)
+		}
+
+		return result
+	}
+
+	BeforeEach(func() {
+		output = &bytes.Buffer{}
+		now = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+		writer := NewJSONWriter(JSONWriterArgs{Writer: output, Interval: time.Second})
+		writer.now = func() time.Time { return now }
+
+		sut = writer.Progress("job")
+	})
+
+	It("reports phase changes", func() {
+		sut.SetPhase(PhaseResolving)
+		sut.SetPhase(PhaseDownloading)
+
+		Expect(events()).To(Equal([]Event{
+			{ID: "job", Phase: PhaseResolving, Time: now},
+			{ID: "job", Phase: PhaseDownloading, Time: now},
+		}))
+	})
+
+	It("reports the transfer periodically with its rate and remaining time", func() {
+		sut.SetPhase(PhaseDownloading)
+		sut.SetTotal(1000)
+		sut.SetDone(100)
+
+		now = now.Add(500 * time.Millisecond)
+		sut.SetDone(200)
+
+		now = now.Add(500 * time.Millisecond)
+		sut.SetDone(300)
+
+		result := events()
+		Expect(result).To(HaveLen(2))
+		Expect(result[1].BytesDone).To(Equal(int64(300)))
+		Expect(result[1].BytesTotal).To(Equal(int64(1000)))
+		Expect(result[1].BytesPerSecond).To(Equal(200.0))
+		Expect(result[1].ETA).To(Equal(3.5))
+	})
+
+	It("reports the completion of the transfer", func() {
+		sut.SetPhase(PhaseDownloading)
+		sut.SetTotal(1000)
+		sut.SetDone(1000)
+
+		result := events()
+		Expect(result).To(HaveLen(2))
+		Expect(result[1].BytesDone).To(Equal(int64(1000)))
+		Expect(result[1].ETA).To(BeZero())
+	})
+
+	It("resets the rate on phase changes", func() {
+		sut.SetPhase(PhaseDownloading)
+		sut.SetTotal(1000)
+		sut.SetDone(0)
+
+		now = now.Add(time.Second)
+		sut.SetDone(1000)
+		sut.SetPhase(PhaseVerifying)
+
+		result := events()
+		Expect(result[len(result)-1]).To(Equal(Event{
+			ID:         "job",
+			Phase:      PhaseVerifying,
+			BytesDone:  1000,
+			BytesTotal: 1000,
+			Time:       now,
+		}))
+	})
+})
@@ -0,0 +1,52 @@
+package progress
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProgress(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Progress Suite")
+}
+
+var _ = Describe("Progress", func() {
+	var (
+		ctrl         *gomock.Controller
+		mockProgress *MockProgress
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockProgress = NewMockProgress(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("reports the bytes written to the writer", func() {
+		gomock.InOrder(
+			mockProgress.EXPECT().SetDone(int64(12)),
+			mockProgress.EXPECT().SetDone(int64(15)),
+		)
+
+		writer := Writer(mockProgress, 10)
+
+		_, err := writer.Write([]byte("ab"))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = bytes.NewBufferString("cde").WriteTo(writer)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("discards the reports", func() {
+		Discard.SetPhase(PhaseDownloading)
+		Discard.SetTotal(10)
+		Discard.SetDone(5)
+	})
+})
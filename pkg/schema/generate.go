@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	describerType = reflect.TypeOf((*Describer)(nil)).Elem()
+	timeType      = reflect.TypeOf(time.Time{})
+)
+
+// Generate returns the schema of the JSON representation of the specified value. Struct fields are
+// named after their json tag, fields tagged with omitempty are optional and the description tag
+// documents the field.
+func Generate(v interface{}) *Schema {
+	return generate(reflect.TypeOf(v))
+}
+
+func generate(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	if t.Implements(describerType) {
+		return reflect.Zero(t).Interface().(Describer).JSONSchema()
+	}
+
+	if t == timeType {
+		return DateTime()
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return generate(t.Elem())
+	case reflect.Struct:
+		return generateObject(t)
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: generate(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: generate(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		return &Schema{}
+	}
+}
+
+func generateObject(t reflect.Type) *Schema {
+	result := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitEmpty, ok := FieldName(field)
+		if !ok {
+			continue
+		}
+
+		property := generate(field.Type)
+		if description := field.Tag.Get("description"); description != "" {
+			copied := *property
+			copied.Description = description
+			property = &copied
+		}
+
+		result.Properties[name] = property
+
+		if !omitEmpty {
+			result.Required = append(result.Required, name)
+		}
+	}
+
+	return result
+}
+
+// FieldName returns the JSON name of the struct field and whether it is omitted when empty. It
+// reports false for fields excluded from the JSON representation.
+func FieldName(field reflect.StructField) (string, bool, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+
+	if name == "" {
+		name = field.Name
+	}
+
+	omitEmpty := false
+
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return name, omitEmpty, true
+}
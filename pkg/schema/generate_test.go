@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type testItem struct {
+	Name string `json:"name"`
+}
+
+type testDescribed struct{}
+
+func (testDescribed) JSONSchema() *Schema {
+	return Of("string")
+}
+
+type testResult struct {
+	ID          int64             `json:"id" description:"identifier of the item"`
+	Price       float64           `json:"price,omitempty"`
+	Enabled     bool              `json:"enabled"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	Tags        []string          `json:"tags,omitempty"`
+	Items       []testItem        `json:"items"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Described   testDescribed     `json:"described"`
+	Pointer     *testItem         `json:"pointer,omitempty"`
+	Untagged    string
+	Ignored     string `json:"-"`
+	notExported string
+}
+
+var _ = Describe("Generate", func() {
+	It("describes structs", func() {
+		result := Generate(testResult{})
+
+		Expect(result).To(Equal(&Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"id":        {Type: "integer", Description: "identifier of the item"},
+				"price":     {Type: "number"},
+				"enabled":   {Type: "boolean"},
+				"createdAt": {Type: "string", Format: "date-time"},
+				"tags":      {Type: "array", Items: &Schema{Type: "string"}},
+				"items": {Type: "array", Items: &Schema{
+					Type:       "object",
+					Properties: map[string]*Schema{"name": {Type: "string"}},
+					Required:   []string{"name"},
+				}},
+				"labels":    {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+				"described": {Type: "string"},
+				"pointer": {
+					Type:       "object",
+					Properties: map[string]*Schema{"name": {Type: "string"}},
+					Required:   []string{"name"},
+				},
+				"Untagged": {Type: "string"},
+			},
+			Required: []string{"id", "enabled", "createdAt", "items", "described", "Untagged"},
+		}))
+	})
+
+	It("describes scalars", func() {
+		Expect(Generate("")).To(Equal(&Schema{Type: "string"}))
+		Expect(Generate(1)).To(Equal(&Schema{Type: "integer"}))
+		Expect(Generate(nil)).To(Equal(&Schema{}))
+	})
+})
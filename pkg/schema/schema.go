@@ -0,0 +1,47 @@
+package schema
+
+const (
+	// Draft is the JSON Schema dialect of the generated schemas.
+	Draft = "https://json-schema.org/draft/2020-12/schema"
+)
+
+// Schema is a subset of JSON Schema sufficient to describe the output of the commands.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Const                interface{}        `json:"const,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Describer is implemented by types whose JSON representation cannot be derived from their fields,
+// such as types with custom marshalers.
+type Describer interface {
+	JSONSchema() *Schema
+}
+
+// Object returns the schema of an object with the specified properties, all of which are optional.
+func Object(properties map[string]*Schema) *Schema {
+	return &Schema{Type: "object", Properties: properties}
+}
+
+// ArrayOf returns the schema of an array with elements of the specified schema.
+func ArrayOf(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+// Of returns the schema of the specified JSON type, e.g. string or integer.
+func Of(jsonType string) *Schema {
+	return &Schema{Type: jsonType}
+}
+
+// DateTime returns the schema of a timestamp formatted as RFC 3339.
+func DateTime() *Schema {
+	return &Schema{Type: "string", Format: "date-time"}
+}
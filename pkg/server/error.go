@@ -0,0 +1,93 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	gohttp "net/http"
+
+	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/majd/ipatool/v2/pkg/hook"
+	"github.com/majd/ipatool/v2/pkg/jobs"
+)
+
+// Codes of the errors originating from the server rather than the App Store.
+const (
+	ErrorCodeInvalidRequest  appstore.ErrorCode = "invalid_request"
+	ErrorCodeUnauthorized    appstore.ErrorCode = "unauthorized"
+	ErrorCodeNotFound        appstore.ErrorCode = "not_found"
+	ErrorCodeJobNotFinished  appstore.ErrorCode = "job_not_finished"
+	ErrorCodeInvalidJobState appstore.ErrorCode = "invalid_job_state"
+)
+
+// statusCodes maps the codes of App Store errors to HTTP status codes. Errors of the App Store which
+// are not listed, e.g. invalid credentials, are reported as a bad gateway.
+var statusCodes = map[appstore.ErrorCode]int{
+	appstore.ErrorCodeUnknown:                  gohttp.StatusInternalServerError,
+	appstore.ErrorCodeLicenseRequired:          gohttp.StatusPaymentRequired,
+	appstore.ErrorCodeSubscriptionRequired:     gohttp.StatusPaymentRequired,
+	appstore.ErrorCodeLicenseAlreadyExists:     gohttp.StatusConflict,
+	appstore.ErrorCodeAppNotFound:              gohttp.StatusNotFound,
+	appstore.ErrorCodeDeveloperNotFound:        gohttp.StatusNotFound,
+	appstore.ErrorCodeRateLimited:              gohttp.StatusTooManyRequests,
+	appstore.ErrorCodeTemporarilyUnavailable:   gohttp.StatusServiceUnavailable,
+	appstore.ErrorCodePackageExists:            gohttp.StatusConflict,
+	appstore.ErrorCodeInsufficientDiskSpace:    gohttp.StatusInsufficientStorage,
+	appstore.ErrorCode(hook.ErrorCodeRejected): gohttp.StatusUnprocessableEntity,
+	appstore.ErrorCodeNotAuthenticated:         gohttp.StatusUnauthorized,
+}
+
+// requestError is an error caused by the request rather than by the App Store.
+type requestError struct {
+	status  int
+	code    appstore.ErrorCode
+	message string
+}
+
+func newRequestError(status int, code appstore.ErrorCode, format string, args ...interface{}) *requestError {
+	return &requestError{
+		status:  status,
+		code:    code,
+		message: fmt.Sprintf(format, args...),
+	}
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+// StatusCodeOf returns the HTTP status code and the error code the specified error is reported with.
+func StatusCodeOf(err error) (int, appstore.ErrorCode) {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return reqErr.status, reqErr.code
+	}
+
+	switch {
+	case errors.Is(err, jobs.ErrJobNotFound):
+		return gohttp.StatusNotFound, ErrorCodeNotFound
+	case errors.Is(err, jobs.ErrInvalidState):
+		return gohttp.StatusConflict, ErrorCodeInvalidJobState
+	}
+
+	code := appstore.ErrorCodeOf(err)
+
+	if status, ok := statusCodes[code]; ok {
+		return status, code
+	}
+
+	return gohttp.StatusBadGateway, code
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code" description:"stable identifier of the class of the error"`
+}
+
+func writeError(w gohttp.ResponseWriter, err error) {
+	status, code := StatusCodeOf(err)
+
+	writeJSON(w, status, errorResponse{
+		Error: err.Error(),
+		Code:  string(code),
+	})
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	gohttp "net/http"
+
+	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/majd/ipatool/v2/pkg/hook"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Error", func() {
+	DescribeTable("maps errors to status codes",
+		func(err error, status int, code appstore.ErrorCode) {
+			actualStatus, actualCode := StatusCodeOf(err)
+			Expect(actualStatus).To(Equal(status))
+			Expect(actualCode).To(Equal(code))
+		},
+		Entry("request error", newRequestError(gohttp.StatusBadRequest, ErrorCodeInvalidRequest, "invalid"), gohttp.StatusBadRequest, ErrorCodeInvalidRequest),
+		Entry("app not found", fmt.Errorf("wrapped: %w", appstore.ErrAppNotFound), gohttp.StatusNotFound, appstore.ErrorCodeAppNotFound),
+		Entry("license required", appstore.ErrLicenseRequired, gohttp.StatusPaymentRequired, appstore.ErrorCodeLicenseRequired),
+		Entry("password token expired", appstore.ErrPasswordTokenExpired, gohttp.StatusBadGateway, appstore.ErrorCodePasswordTokenExpired),
+		Entry("rate limited", &appstore.Error{StatusCode: gohttp.StatusTooManyRequests}, gohttp.StatusTooManyRequests, appstore.ErrorCodeRateLimited),
+		Entry("not authenticated", appstore.ErrNotAuthenticated, gohttp.StatusUnauthorized, appstore.ErrorCodeNotAuthenticated),
+		Entry("hook rejected", fmt.Errorf("%w: \"exit 1\" exited with status 1", hook.ErrRejected), gohttp.StatusUnprocessableEntity, appstore.ErrorCode(hook.ErrorCodeRejected)),
+		Entry("unknown", errors.New("failed"), gohttp.StatusInternalServerError, appstore.ErrorCodeUnknown),
+	)
+})
@@ -0,0 +1,19 @@
+package server
+
+import (
+	"github.com/majd/ipatool/v2/pkg/jobs"
+)
+
+// DownloadRequest describes the app package to download.
+type DownloadRequest struct {
+	AppID             int64  `json:"appID,omitempty" description:"ID of the app; ignored when the bundle identifier is specified"`
+	BundleID          string `json:"bundleID,omitempty"`
+	ExternalVersionID string `json:"externalVersionID,omitempty" description:"defaults to the latest version"`
+	Platform          string `json:"platform,omitempty" description:"iphone, ipad or appletv"`
+	Purchase          bool   `json:"purchase,omitempty" description:"obtain a license for the app if needed"`
+}
+
+type jobListResponse struct {
+	Count int        `json:"count"`
+	Jobs  []jobs.Job `json:"jobs"`
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	gohttp "net/http"
+	"strconv"
+	"strings"
+
+	"github.com/majd/ipatool/v2/pkg/schema"
+)
+
+const (
+	openAPIVersion = "3.1.0"
+	openAPIPath    = "/openapi.json"
+	securityScheme = "bearerAuth"
+)
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+	Security   []map[string][]string            `json:"security"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Required    bool           `json:"required,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Schema      *schema.Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *schema.Schema `json:"schema"`
+}
+
+type Components struct {
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+func (s *server) OpenAPI() Document {
+	paths := map[string]map[string]*Operation{}
+
+	for _, r := range s.routes {
+		if paths[r.path] == nil {
+			paths[r.path] = map[string]*Operation{}
+		}
+
+		paths[r.path][strings.ToLower(r.method)] = operation(r)
+	}
+
+	return Document{
+		OpenAPI: openAPIVersion,
+		Info: Info{
+			Title:   "ipatool",
+			Version: s.version,
+		},
+		Paths: paths,
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				securityScheme: {Type: "http", Scheme: "bearer"},
+			},
+		},
+		Security: []map[string][]string{{securityScheme: {}}},
+	}
+}
+
+func operation(r route) *Operation {
+	op := &Operation{
+		OperationID: r.operationID,
+		Summary:     r.summary,
+		Parameters:  r.parameters,
+		Responses: map[string]*Response{
+			strconv.Itoa(r.status): {
+				Description: gohttp.StatusText(r.status),
+				Content:     jsonContent(r.response),
+			},
+			"default": {
+				Description: "Error",
+				Content:     jsonContent(errorResponse{}),
+			},
+		},
+	}
+
+	if r.stream != nil {
+		op.Responses[strconv.Itoa(r.status)].Content = map[string]MediaType{
+			"application/octet-stream": {Schema: &schema.Schema{Type: "string", Format: "binary"}},
+		}
+	}
+
+	if r.request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(r.request),
+		}
+	}
+
+	return op
+}
+
+func jsonContent(v interface{}) map[string]MediaType {
+	return map[string]MediaType{
+		"application/json": {Schema: schema.Generate(v)},
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	gohttp "net/http"
+
+	"github.com/majd/ipatool/v2/pkg/log"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Server (OpenAPI)", func() {
+	var sut Server
+
+	BeforeEach(func() {
+		sut = New(Args{
+			Logger:  log.NewLogger(log.Args{Writer: io.Discard}),
+			Token:   "token",
+			Version: "1.2.3",
+		})
+	})
+
+	It("describes every route", func() {
+		document := sut.OpenAPI()
+		Expect(document.OpenAPI).To(Equal("3.1.0"))
+		Expect(document.Info.Version).To(Equal("1.2.3"))
+		Expect(document.Paths).To(HaveKey("/v1/search"))
+		Expect(document.Paths).To(HaveKey("/v1/apps/{appID}/versions/{versionID}"))
+		Expect(document.Paths).To(HaveKey("/v1/jobs/{jobID}/package"))
+
+		for _, r := range sut.(*server).routes {
+			Expect(document.Paths[r.path]).To(HaveKey(map[string]string{"GET": "get", "POST": "post"}[r.method]))
+		}
+	})
+
+	It("derives the schemas from the request and response types", func() {
+		download := sut.OpenAPI().Paths["/v1/downloads"]["post"]
+		Expect(download.RequestBody.Content["application/json"].Schema.Properties).To(HaveKey("bundleID"))
+		Expect(download.Responses).To(HaveKey("202"))
+		Expect(download.Responses["202"].Content["application/json"].Schema.Required).To(ContainElements("id", "status"))
+		Expect(download.Responses["default"].Content["application/json"].Schema.Required).To(Equal([]string{"error", "code"}))
+
+		pkg := sut.OpenAPI().Paths["/v1/jobs/{jobID}/package"]["get"]
+		Expect(pkg.Responses["200"].Content).To(HaveKey("application/octet-stream"))
+	})
+
+	It("serves the document without authentication", func() {
+		res, _ := send(sut.Handler(), gohttp.MethodGet, "/openapi.json", "", "")
+		Expect(res.Code).To(Equal(gohttp.StatusOK))
+
+		var document Document
+		Expect(json.Unmarshal(res.Body.Bytes(), &document)).To(Succeed())
+		Expect(document.Components.SecuritySchemes).To(HaveKey("bearerAuth"))
+	})
+})
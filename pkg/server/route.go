@@ -0,0 +1,154 @@
+package server
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	gohttp "net/http"
+	"strconv"
+	"strings"
+
+	"github.com/majd/ipatool/v2/pkg/log"
+	"github.com/majd/ipatool/v2/pkg/schema"
+	"github.com/rs/zerolog"
+)
+
+// route is an endpoint of the API. The OpenAPI document is generated from the routes, so the
+// parameters, request and response of a route must match what its handler reads and returns.
+type route struct {
+	method      string
+	path        string
+	operationID string
+	summary     string
+	parameters  []Parameter
+	// request is the zero value of the request body, or nil if the route has no request body.
+	request interface{}
+	// response is the zero value of the response body.
+	response interface{}
+	status   int
+	// handler returns the response body of JSON routes.
+	handler func(req *gohttp.Request) (interface{}, error)
+	// stream writes the response of routes which do not respond with JSON. It takes precedence over handler.
+	stream func(w gohttp.ResponseWriter, req *gohttp.Request) error
+}
+
+func (r route) serve(w gohttp.ResponseWriter, req *gohttp.Request) error {
+	if r.stream != nil {
+		return r.stream(w, req)
+	}
+
+	body, err := r.handler(req)
+	if err != nil {
+		return err
+	}
+
+	writeJSON(w, r.status, body)
+
+	return nil
+}
+
+// authenticate returns an error unless the request carries the bearer token of the server. Requests
+// are not authenticated when the server has no token.
+func (s *server) authenticate(req *gohttp.Request) error {
+	if s.token == "" {
+		return nil
+	}
+
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		return newRequestError(gohttp.StatusUnauthorized, ErrorCodeUnauthorized, "missing or invalid bearer token")
+	}
+
+	return nil
+}
+
+// writeJSON writes the value with the same field names and order as the output of the commands.
+func writeJSON(w gohttp.ResponseWriter, status int, v interface{}) {
+	var buf bytes.Buffer
+
+	if _, isDocument := v.(Document); isDocument {
+		_ = json.NewEncoder(&buf).Encode(v)
+	} else {
+		logger := zerolog.New(&buf)
+		log.Fields(logger.Log(), v).Send()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// decodeBody decodes the JSON request body into v.
+func decodeBody(req *gohttp.Request, v interface{}) error {
+	decoder := json.NewDecoder(req.Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(v)
+	if err != nil {
+		return newRequestError(gohttp.StatusBadRequest, ErrorCodeInvalidRequest, "invalid request body: %s", err)
+	}
+
+	return nil
+}
+
+func queryInt64(req *gohttp.Request, name string, fallback int64) (int64, error) {
+	value := req.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+
+	result, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, newRequestError(gohttp.StatusBadRequest, ErrorCodeInvalidRequest, "invalid value %q for parameter %q", value, name)
+	}
+
+	return result, nil
+}
+
+func queryBool(req *gohttp.Request, name string) (bool, error) {
+	value := req.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, newRequestError(gohttp.StatusBadRequest, ErrorCodeInvalidRequest, "invalid value %q for parameter %q", value, name)
+	}
+
+	return result, nil
+}
+
+// queryList returns the values of a query parameter which can be repeated or contain comma-separated values.
+func queryList(req *gohttp.Request, name string) []string {
+	var result []string
+
+	for _, value := range req.URL.Query()[name] {
+		for _, item := range strings.Split(value, ",") {
+			if item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+
+	return result
+}
+
+func pathInt64(req *gohttp.Request, name string) (int64, error) {
+	value := req.PathValue(name)
+
+	result, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, newRequestError(gohttp.StatusBadRequest, ErrorCodeInvalidRequest, "invalid value %q for parameter %q", value, name)
+	}
+
+	return result, nil
+}
+
+func queryParameter(name string, jsonType string, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema.Of(jsonType)}
+}
+
+func pathParameter(name string, jsonType string, description string) Parameter {
+	return Parameter{Name: name, In: "path", Required: true, Description: description, Schema: schema.Of(jsonType)}
+}
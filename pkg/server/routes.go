@@ -0,0 +1,147 @@
+package server
+
+import (
+	gohttp "net/http"
+
+	"github.com/majd/ipatool/v2/pkg/jobs"
+	"github.com/majd/ipatool/v2/pkg/schema"
+)
+
+var (
+	platformParameter = queryParameter("platform", "string", "platform of the apps: iphone, ipad or appletv")
+	countryParameter  = queryParameter("country", "string", "two-letter code of the country (defaults to the account's country)")
+	fieldsParameter   = queryParameter("fields", "string", "comma-separated app fields to include (defaults to all fields)")
+	appIDParameter    = pathParameter("appID", "integer", "ID of the app")
+	jobIDParameter    = pathParameter("jobID", "string", "ID of the job")
+)
+
+func (s *server) apiRoutes() []route {
+	return []route{
+		{
+			method:      gohttp.MethodGet,
+			path:        "/v1/search",
+			operationID: "search",
+			summary:     "Search for apps on the App Store",
+			parameters: []Parameter{
+				{Name: "term", In: "query", Required: true, Description: "search term", Schema: schema.Of("string")},
+				queryParameter("limit", "integer", "maximum number of results (defaults to 5)"),
+				queryParameter("offset", "integer", "number of matching results to skip"),
+				queryParameter("sort", "string", "relevance, name, price, rating, rating-count or release-date"),
+				queryParameter("desc", "boolean", "sort in descending order"),
+				platformParameter,
+				countryParameter,
+				fieldsParameter,
+			},
+			response: searchResponse{},
+			status:   gohttp.StatusOK,
+			handler:  s.search,
+		},
+		{
+			method:      gohttp.MethodGet,
+			path:        "/v1/apps",
+			operationID: "lookup",
+			summary:     "Look apps up by app ID or bundle identifier",
+			parameters: []Parameter{
+				queryParameter("id", "string", "comma-separated app IDs"),
+				queryParameter("bundleID", "string", "comma-separated bundle identifiers"),
+				platformParameter,
+				countryParameter,
+				fieldsParameter,
+			},
+			response: lookupResponse{},
+			status:   gohttp.StatusOK,
+			handler:  s.lookup,
+		},
+		{
+			method:      gohttp.MethodGet,
+			path:        "/v1/apps/{appID}/versions",
+			operationID: "listVersions",
+			summary:     "List the available versions of an app",
+			parameters:  []Parameter{appIDParameter},
+			response:    listVersionsResponse{},
+			status:      gohttp.StatusOK,
+			handler:     s.listVersions,
+		},
+		{
+			method:      gohttp.MethodGet,
+			path:        "/v1/apps/{appID}/versions/{versionID}",
+			operationID: "getVersionMetadata",
+			summary:     "Get the metadata of a version of an app",
+			parameters: []Parameter{
+				appIDParameter,
+				pathParameter("versionID", "string", "external version identifier"),
+			},
+			response: versionMetadataResponse{},
+			status:   gohttp.StatusOK,
+			handler:  s.versionMetadata,
+		},
+		{
+			method:      gohttp.MethodPost,
+			path:        "/v1/purchases",
+			operationID: "purchase",
+			summary:     "Obtain a license for a free app",
+			request:     purchaseRequest{},
+			response:    purchaseResponse{},
+			status:      gohttp.StatusOK,
+			handler:     s.purchase,
+		},
+		{
+			method:      gohttp.MethodPost,
+			path:        "/v1/downloads",
+			operationID: "createDownload",
+			summary:     "Start a job downloading an app package",
+			request:     DownloadRequest{},
+			response:    jobs.Job{},
+			status:      gohttp.StatusAccepted,
+			handler:     s.createDownload,
+		},
+		{
+			method:      gohttp.MethodGet,
+			path:        "/v1/jobs",
+			operationID: "listJobs",
+			summary:     "List the download jobs",
+			response:    jobListResponse{},
+			status:      gohttp.StatusOK,
+			handler:     s.listJobs,
+		},
+		{
+			method:      gohttp.MethodGet,
+			path:        "/v1/jobs/{jobID}",
+			operationID: "getJob",
+			summary:     "Get the status of a download job",
+			parameters:  []Parameter{jobIDParameter},
+			response:    jobs.Job{},
+			status:      gohttp.StatusOK,
+			handler:     s.getJob,
+		},
+		{
+			method:      gohttp.MethodPost,
+			path:        "/v1/jobs/{jobID}/cancel",
+			operationID: "cancelJob",
+			summary:     "Cancel a queued or running download job",
+			parameters:  []Parameter{jobIDParameter},
+			response:    jobs.Job{},
+			status:      gohttp.StatusOK,
+			handler:     s.cancelJob,
+		},
+		{
+			method:      gohttp.MethodPost,
+			path:        "/v1/jobs/{jobID}/retry",
+			operationID: "retryJob",
+			summary:     "Queue a failed or cancelled download job again",
+			parameters:  []Parameter{jobIDParameter},
+			response:    jobs.Job{},
+			status:      gohttp.StatusOK,
+			handler:     s.retryJob,
+		},
+		{
+			method:      gohttp.MethodGet,
+			path:        "/v1/jobs/{jobID}/package",
+			operationID: "getJobPackage",
+			summary:     "Download the app package of a succeeded download job",
+			parameters:  []Parameter{jobIDParameter},
+			status:      gohttp.StatusOK,
+			stream:      s.streamPackage,
+		},
+	}
+}
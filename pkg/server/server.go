@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	gohttp "net/http"
+	"time"
+
+	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/majd/ipatool/v2/pkg/jobs"
+	"github.com/majd/ipatool/v2/pkg/log"
+)
+
+// shutdownTimeout is how long in-flight requests are given to complete when the server stops.
+const shutdownTimeout = 10 * time.Second
+
+// Server exposes the App Store operations as a JSON REST API.
+//
+//go:generate go run go.uber.org/mock/mockgen -source=server.go -destination=server_mock.go -package server
+type Server interface {
+	// Handler returns the HTTP handler serving the API.
+	Handler() gohttp.Handler
+	// OpenAPI returns the OpenAPI document describing the API.
+	OpenAPI() Document
+	// Serve accepts connections on the listener until the context is cancelled.
+	Serve(ctx context.Context, listener net.Listener) error
+}
+
+type server struct {
+	appStore   appstore.AppStore
+	jobs       jobs.Queue
+	outputPath string
+	logger     log.Logger
+	token      string
+	version    string
+	routes     []route
+}
+
+type Args struct {
+	AppStore appstore.AppStore
+	// Jobs is the queue the download jobs are added to. The server does not run the jobs.
+	Jobs jobs.Queue
+	// OutputPath is the directory the download jobs save app packages to.
+	OutputPath string
+	Logger     log.Logger
+	// Token is the bearer token clients must authenticate with. Requests are not authenticated when it is empty.
+	Token string
+	// Version is the version of the API reported in the OpenAPI document.
+	Version string
+}
+
+func New(args Args) Server {
+	s := &server{
+		appStore:   args.AppStore,
+		jobs:       args.Jobs,
+		outputPath: args.OutputPath,
+		logger:     args.Logger,
+		token:      args.Token,
+		version:    args.Version,
+	}
+
+	s.routes = s.apiRoutes()
+
+	return s
+}
+
+func (s *server) Handler() gohttp.Handler {
+	mux := gohttp.NewServeMux()
+
+	for _, r := range s.routes {
+		mux.Handle(r.method+" "+r.path, s.handle(r))
+	}
+
+	mux.HandleFunc("GET "+openAPIPath, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		writeJSON(w, gohttp.StatusOK, s.OpenAPI())
+	})
+
+	mux.HandleFunc("/", func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		writeError(w, newRequestError(gohttp.StatusNotFound, ErrorCodeNotFound, "no route matches %s %s", r.Method, r.URL.Path))
+	})
+
+	return mux
+}
+
+func (s *server) Serve(ctx context.Context, listener net.Listener) error {
+	httpServer := &gohttp.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("failed to serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	err := httpServer.Shutdown(shutdownCtx)
+	if err != nil {
+		return fmt.Errorf("failed to shut down: %w", err)
+	}
+
+	err = <-errs
+	if err != nil && !errors.Is(err, gohttp.ErrServerClosed) {
+		return fmt.Errorf("failed to serve: %w", err)
+	}
+
+	return nil
+}
+
+// handle wraps the handler of the route with authentication, error handling and logging.
+func (s *server) handle(r route) gohttp.Handler {
+	return gohttp.HandlerFunc(func(w gohttp.ResponseWriter, req *gohttp.Request) {
+		start := time.Now()
+
+		err := s.authenticate(req)
+		if err == nil {
+			err = r.serve(w, req)
+		}
+
+		if err != nil {
+			writeError(w, err)
+		}
+
+		s.logger.Verbose().
+			Str("method", req.Method).
+			Str("path", req.URL.Path).
+			Dur("duration", time.Since(start)).
+			Err(err).
+			Msg("request")
+	})
+}
@@ -0,0 +1,322 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	gohttp "net/http"
+	"strconv"
+	"time"
+
+	"github.com/majd/ipatool/v2/pkg/appstore"
+)
+
+type searchResponse struct {
+	Count   int                   `json:"count"`
+	Total   int                   `json:"total" description:"number of matching apps before pagination, at most 200"`
+	Offset  int64                 `json:"offset"`
+	HasMore bool                  `json:"hasMore"`
+	Apps    appstore.AppSelection `json:"apps"`
+}
+
+type lookupResponse struct {
+	Count             int                   `json:"count"`
+	Apps              appstore.AppSelection `json:"apps"`
+	NotFoundAppIDs    []int64               `json:"notFoundAppIDs"`
+	NotFoundBundleIDs []string              `json:"notFoundBundleIDs"`
+}
+
+type listVersionsResponse struct {
+	ExternalVersionIdentifiers []string `json:"externalVersionIdentifiers"`
+	LatestExternalVersionID    string   `json:"latestExternalVersionID"`
+}
+
+type versionMetadataResponse struct {
+	ExternalVersionID string    `json:"externalVersionID"`
+	DisplayVersion    string    `json:"displayVersion"`
+	ReleaseDate       time.Time `json:"releaseDate"`
+}
+
+type purchaseRequest struct {
+	AppID    int64  `json:"appID,omitempty" description:"ID of the app; ignored when the bundle identifier is specified"`
+	BundleID string `json:"bundleID,omitempty"`
+}
+
+type purchaseResponse struct {
+	AlreadyOwned bool `json:"alreadyOwned"`
+}
+
+// nolint:wrapcheck
+func (s *server) search(req *gohttp.Request) (interface{}, error) {
+	query := req.URL.Query()
+
+	term := query.Get("term")
+	if term == "" {
+		return nil, newRequestError(gohttp.StatusBadRequest, ErrorCodeInvalidRequest, "parameter \"term\" is required")
+	}
+
+	limit, err := queryInt64(req, "limit", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, err := queryInt64(req, "offset", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	descending, err := queryBool(req, "desc")
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := appstore.ParseSearchSort(query.Get("sort"))
+	if err != nil {
+		return nil, newRequestError(gohttp.StatusBadRequest, ErrorCodeInvalidRequest, "%s", err)
+	}
+
+	platform, fields, err := parseAppQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	acc, err := s.countryAccount(query.Get("country"))
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := s.appStore.Search(appstore.SearchInput{
+		Account:    acc,
+		Term:       term,
+		Limit:      limit,
+		Offset:     offset,
+		Platform:   platform,
+		Country:    query.Get("country"),
+		Sort:       order,
+		Descending: descending,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return searchResponse{
+		Count:   output.Count,
+		Total:   output.Total,
+		Offset:  offset,
+		HasMore: output.HasMore,
+		Apps:    appstore.Apps(output.Results).WithFields(fields),
+	}, nil
+}
+
+// nolint:wrapcheck
+func (s *server) lookup(req *gohttp.Request) (interface{}, error) {
+	var ids []int64
+
+	for _, value := range queryList(req, "id") {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, newRequestError(gohttp.StatusBadRequest, ErrorCodeInvalidRequest, "invalid value %q for parameter \"id\"", value)
+		}
+
+		ids = append(ids, id)
+	}
+
+	bundleIDs := queryList(req, "bundleID")
+
+	if len(ids) == 0 && len(bundleIDs) == 0 {
+		return nil, newRequestError(gohttp.StatusBadRequest, ErrorCodeInvalidRequest, "at least one app ID or bundle identifier must be specified")
+	}
+
+	platform, fields, err := parseAppQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	country := req.URL.Query().Get("country")
+
+	acc, err := s.countryAccount(country)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := s.appStore.LookupBatch(appstore.LookupBatchInput{
+		Account:   acc,
+		AppIDs:    ids,
+		BundleIDs: bundleIDs,
+		Platform:  platform,
+		Country:   country,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lookupResponse{
+		Count:             len(output.Apps),
+		Apps:              appstore.Apps(output.Apps).WithFields(fields),
+		NotFoundAppIDs:    output.NotFoundAppIDs,
+		NotFoundBundleIDs: output.NotFoundBundleIDs,
+	}, nil
+}
+
+// nolint:wrapcheck
+func (s *server) listVersions(req *gohttp.Request) (interface{}, error) {
+	appID, err := pathInt64(req, "appID")
+	if err != nil {
+		return nil, err
+	}
+
+	var output appstore.ListVersionsOutput
+
+	err = s.withAccount(func(acc appstore.Account) error {
+		output, err = s.appStore.ListVersions(appstore.ListVersionsInput{Account: acc, App: appstore.App{ID: appID}})
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return listVersionsResponse{
+		ExternalVersionIdentifiers: output.ExternalVersionIdentifiers,
+		LatestExternalVersionID:    output.LatestExternalVersionID,
+	}, nil
+}
+
+// nolint:wrapcheck
+func (s *server) versionMetadata(req *gohttp.Request) (interface{}, error) {
+	appID, err := pathInt64(req, "appID")
+	if err != nil {
+		return nil, err
+	}
+
+	versionID := req.PathValue("versionID")
+
+	var output appstore.GetVersionMetadataOutput
+
+	err = s.withAccount(func(acc appstore.Account) error {
+		output, err = s.appStore.GetVersionMetadata(appstore.GetVersionMetadataInput{
+			Account:   acc,
+			App:       appstore.App{ID: appID},
+			VersionID: versionID,
+		})
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return versionMetadataResponse{
+		ExternalVersionID: versionID,
+		DisplayVersion:    output.DisplayVersion,
+		ReleaseDate:       output.ReleaseDate,
+	}, nil
+}
+
+// nolint:wrapcheck
+func (s *server) purchase(req *gohttp.Request) (interface{}, error) {
+	var body purchaseRequest
+
+	err := decodeBody(req, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	if body.AppID == 0 && body.BundleID == "" {
+		return nil, newRequestError(gohttp.StatusBadRequest, ErrorCodeInvalidRequest, "either the app ID or the bundle identifier must be specified")
+	}
+
+	var alreadyOwned bool
+
+	err = s.withAccount(func(acc appstore.Account) error {
+		app := appstore.App{ID: body.AppID}
+
+		if body.BundleID != "" {
+			lookupResult, err := s.appStore.Lookup(appstore.LookupInput{Account: acc, BundleID: body.BundleID})
+			if err != nil {
+				return err
+			}
+
+			app = lookupResult.App
+		}
+
+		err := s.appStore.Purchase(appstore.PurchaseInput{Account: acc, App: app})
+		if err != nil && !errors.Is(err, appstore.ErrLicenseAlreadyExists) {
+			return err
+		}
+
+		alreadyOwned = errors.Is(err, appstore.ErrLicenseAlreadyExists)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return purchaseResponse{AlreadyOwned: alreadyOwned}, nil
+}
+
+// parseAppQuery parses the platform and fields query parameters shared by the routes returning apps.
+func parseAppQuery(req *gohttp.Request) (appstore.Platform, []string, error) {
+	platform, err := appstore.ParsePlatform(req.URL.Query().Get("platform"))
+	if err != nil {
+		return platform, nil, newRequestError(gohttp.StatusBadRequest, ErrorCodeInvalidRequest, "%s", err)
+	}
+
+	fields := queryList(req, "fields")
+
+	err = appstore.ValidateAppFields(fields)
+	if err != nil {
+		return platform, nil, newRequestError(gohttp.StatusBadRequest, ErrorCodeInvalidRequest, "%s", err)
+	}
+
+	return platform, fields, nil
+}
+
+// countryAccount returns the stored account unless the country is specified, in which case the
+// request does not depend on the account's store front.
+// nolint:wrapcheck
+func (s *server) countryAccount(country string) (appstore.Account, error) {
+	if country != "" {
+		return appstore.Account{}, nil
+	}
+
+	infoResult, err := s.appStore.AccountInfo()
+	if err != nil {
+		return appstore.Account{}, err
+	}
+
+	return infoResult.Account, nil
+}
+
+// withAccount runs the operation with the stored account, logging in again and retrying once when
+// the password token expired.
+// nolint:wrapcheck
+func (s *server) withAccount(operation func(acc appstore.Account) error) error {
+	infoResult, err := s.appStore.AccountInfo()
+	if err != nil {
+		return err
+	}
+
+	acc := infoResult.Account
+
+	err = operation(acc)
+	if !errors.Is(err, appstore.ErrPasswordTokenExpired) {
+		return err
+	}
+
+	bagOutput, err := s.appStore.Bag(appstore.BagInput{})
+	if err != nil {
+		return fmt.Errorf("failed to get bag: %w", err)
+	}
+
+	loginResult, err := s.appStore.Login(appstore.LoginInput{
+		Email:    acc.Email,
+		Password: acc.Password,
+		Endpoint: bagOutput.AuthEndpoint,
+	})
+	if err != nil {
+		return err
+	}
+
+	return operation(loginResult.Account)
+}
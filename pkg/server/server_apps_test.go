@@ -0,0 +1,240 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	gohttp "net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/majd/ipatool/v2/pkg/jobs"
+	"github.com/majd/ipatool/v2/pkg/log"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+// send performs a request against the handler, authenticated with the token unless it is empty, and
+// returns the response with its decoded JSON body.
+func send(handler gohttp.Handler, method, target, token, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	var decoded map[string]interface{}
+	_ = json.Unmarshal(res.Body.Bytes(), &decoded)
+
+	return res, decoded
+}
+
+var _ = Describe("Server (Apps)", func() {
+	var (
+		ctrl         *gomock.Controller
+		mockAppStore *appstore.MockAppStore
+		handler      gohttp.Handler
+		acc          = appstore.Account{Email: "test@example.com", Password: "password", StoreFront: "143441"}
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockAppStore = appstore.NewMockAppStore(ctrl)
+		handler = New(Args{
+			AppStore: mockAppStore,
+			Jobs:     jobs.NewMockQueue(ctrl),
+			Logger:   log.NewLogger(log.Args{Writer: io.Discard}),
+			Token:    "token",
+		}).Handler()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	When("the request is not authenticated", func() {
+		It("returns unauthorized", func() {
+			res, body := send(handler, gohttp.MethodGet, "/v1/search?term=app", "", "")
+			Expect(res.Code).To(Equal(gohttp.StatusUnauthorized))
+			Expect(body["code"]).To(Equal("unauthorized"))
+
+			res, _ = send(handler, gohttp.MethodGet, "/v1/search?term=app", "invalid", "")
+			Expect(res.Code).To(Equal(gohttp.StatusUnauthorized))
+		})
+	})
+
+	When("searching", func() {
+		It("returns the matching apps", func() {
+			mockAppStore.EXPECT().
+				Search(appstore.SearchInput{
+					Term:       "app",
+					Limit:      10,
+					Offset:     5,
+					Platform:   appstore.PlatformIPad,
+					Country:    "US",
+					Sort:       appstore.SearchSortName,
+					Descending: true,
+				}).
+				Return(appstore.SearchOutput{
+					Count:   1,
+					Total:   6,
+					Results: []appstore.App{{ID: 1, BundleID: "app.bundle.id", Name: "App"}},
+				}, nil)
+
+			res, body := send(handler, gohttp.MethodGet, "/v1/search?term=app&limit=10&offset=5&platform=ipad&country=US&sort=name&desc=true&fields=id,name", "token", "")
+			Expect(res.Code).To(Equal(gohttp.StatusOK))
+			Expect(res.Header().Get("Content-Type")).To(Equal("application/json"))
+			Expect(body["count"]).To(BeEquivalentTo(1))
+			Expect(body["total"]).To(BeEquivalentTo(6))
+			Expect(body["offset"]).To(BeEquivalentTo(5))
+			Expect(body["apps"]).To(Equal([]interface{}{map[string]interface{}{"id": 1.0, "name": "App"}}))
+		})
+
+		It("uses the account's store front when the country is not specified", func() {
+			mockAppStore.EXPECT().AccountInfo().Return(appstore.AccountInfoOutput{Account: acc}, nil)
+			mockAppStore.EXPECT().
+				Search(gomock.Any()).
+				DoAndReturn(func(input appstore.SearchInput) (appstore.SearchOutput, error) {
+					Expect(input.Account).To(Equal(acc))
+					Expect(input.Limit).To(Equal(int64(5)))
+
+					return appstore.SearchOutput{}, nil
+				})
+
+			res, _ := send(handler, gohttp.MethodGet, "/v1/search?term=app", "token", "")
+			Expect(res.Code).To(Equal(gohttp.StatusOK))
+		})
+
+		DescribeTable("rejects invalid parameters",
+			func(query string) {
+				res, body := send(handler, gohttp.MethodGet, "/v1/search?"+query, "token", "")
+				Expect(res.Code).To(Equal(gohttp.StatusBadRequest))
+				Expect(body["code"]).To(Equal("invalid_request"))
+			},
+			Entry("missing term", "limit=5"),
+			Entry("invalid limit", "term=app&limit=five"),
+			Entry("invalid sort", "term=app&sort=size"),
+			Entry("invalid platform", "term=app&platform=watch"),
+			Entry("invalid fields", "term=app&fields=color"),
+		)
+	})
+
+	When("looking apps up", func() {
+		It("returns the apps and the identifiers without a match", func() {
+			mockAppStore.EXPECT().
+				LookupBatch(appstore.LookupBatchInput{
+					AppIDs:    []int64{1, 2},
+					BundleIDs: []string{"app.bundle.id"},
+					Country:   "US",
+				}).
+				Return(appstore.LookupBatchOutput{
+					Apps:           []appstore.App{{ID: 1, BundleID: "app.bundle.id"}},
+					NotFoundAppIDs: []int64{2},
+				}, nil)
+
+			res, body := send(handler, gohttp.MethodGet, "/v1/apps?id=1,2&bundleID=app.bundle.id&country=US", "token", "")
+			Expect(res.Code).To(Equal(gohttp.StatusOK))
+			Expect(body["count"]).To(BeEquivalentTo(1))
+			Expect(body["notFoundAppIDs"]).To(Equal([]interface{}{2.0}))
+			Expect(body["notFoundBundleIDs"]).To(BeEmpty())
+		})
+
+		It("requires an identifier", func() {
+			res, _ := send(handler, gohttp.MethodGet, "/v1/apps", "token", "")
+			Expect(res.Code).To(Equal(gohttp.StatusBadRequest))
+		})
+	})
+
+	When("listing versions", func() {
+		It("logs in again when the password token expired", func() {
+			renewed := appstore.Account{Email: acc.Email, PasswordToken: "renewed"}
+
+			gomock.InOrder(
+				mockAppStore.EXPECT().AccountInfo().Return(appstore.AccountInfoOutput{Account: acc}, nil),
+				mockAppStore.EXPECT().
+					ListVersions(appstore.ListVersionsInput{Account: acc, App: appstore.App{ID: 1}}).
+					Return(appstore.ListVersionsOutput{}, appstore.ErrPasswordTokenExpired),
+				mockAppStore.EXPECT().Bag(appstore.BagInput{}).Return(appstore.BagOutput{AuthEndpoint: "endpoint"}, nil),
+				mockAppStore.EXPECT().
+					Login(appstore.LoginInput{Email: acc.Email, Password: acc.Password, Endpoint: "endpoint"}).
+					Return(appstore.LoginOutput{Account: renewed}, nil),
+				mockAppStore.EXPECT().
+					ListVersions(appstore.ListVersionsInput{Account: renewed, App: appstore.App{ID: 1}}).
+					Return(appstore.ListVersionsOutput{
+						ExternalVersionIdentifiers: []string{"1", "2"},
+						LatestExternalVersionID:    "2",
+					}, nil),
+			)
+
+			res, body := send(handler, gohttp.MethodGet, "/v1/apps/1/versions", "token", "")
+			Expect(res.Code).To(Equal(gohttp.StatusOK))
+			Expect(body["externalVersionIdentifiers"]).To(Equal([]interface{}{"1", "2"}))
+			Expect(body["latestExternalVersionID"]).To(Equal("2"))
+		})
+
+		It("rejects an invalid app ID", func() {
+			res, _ := send(handler, gohttp.MethodGet, "/v1/apps/abc/versions", "token", "")
+			Expect(res.Code).To(Equal(gohttp.StatusBadRequest))
+		})
+	})
+
+	When("getting the metadata of a version", func() {
+		It("returns the metadata", func() {
+			mockAppStore.EXPECT().AccountInfo().Return(appstore.AccountInfoOutput{Account: acc}, nil)
+			mockAppStore.EXPECT().
+				GetVersionMetadata(appstore.GetVersionMetadataInput{Account: acc, App: appstore.App{ID: 1}, VersionID: "42"}).
+				Return(appstore.GetVersionMetadataOutput{DisplayVersion: "1.2.3"}, nil)
+
+			res, body := send(handler, gohttp.MethodGet, "/v1/apps/1/versions/42", "token", "")
+			Expect(res.Code).To(Equal(gohttp.StatusOK))
+			Expect(body["externalVersionID"]).To(Equal("42"))
+			Expect(body["displayVersion"]).To(Equal("1.2.3"))
+		})
+	})
+
+	When("purchasing", func() {
+		It("looks the app up by bundle identifier", func() {
+			app := appstore.App{ID: 1, BundleID: "app.bundle.id"}
+
+			mockAppStore.EXPECT().AccountInfo().Return(appstore.AccountInfoOutput{Account: acc}, nil)
+			mockAppStore.EXPECT().
+				Lookup(appstore.LookupInput{Account: acc, BundleID: "app.bundle.id"}).
+				Return(appstore.LookupOutput{App: app}, nil)
+			mockAppStore.EXPECT().
+				Purchase(appstore.PurchaseInput{Account: acc, App: app}).
+				Return(appstore.ErrLicenseAlreadyExists)
+
+			res, body := send(handler, gohttp.MethodPost, "/v1/purchases", "token", `{"bundleID":"app.bundle.id"}`)
+			Expect(res.Code).To(Equal(gohttp.StatusOK))
+			Expect(body["alreadyOwned"]).To(BeTrue())
+		})
+
+		It("reports App Store errors with their code", func() {
+			mockAppStore.EXPECT().AccountInfo().Return(appstore.AccountInfoOutput{Account: acc}, nil)
+			mockAppStore.EXPECT().
+				Purchase(gomock.Any()).
+				Return(appstore.ErrSubscriptionRequired)
+
+			res, body := send(handler, gohttp.MethodPost, "/v1/purchases", "token", `{"appID":1}`)
+			Expect(res.Code).To(Equal(gohttp.StatusPaymentRequired))
+			Expect(body["code"]).To(Equal("subscription_required"))
+		})
+
+		It("rejects unknown fields", func() {
+			res, body := send(handler, gohttp.MethodPost, "/v1/purchases", "token", `{"app":1}`)
+			Expect(res.Code).To(Equal(gohttp.StatusBadRequest))
+			Expect(body["error"]).To(ContainSubstring("unknown field"))
+		})
+	})
+
+	When("the route does not exist", func() {
+		It("returns not found", func() {
+			res, body := send(handler, gohttp.MethodGet, "/v1/unknown", "token", "")
+			Expect(res.Code).To(Equal(gohttp.StatusNotFound))
+			Expect(body["code"]).To(Equal("not_found"))
+		})
+	})
+})
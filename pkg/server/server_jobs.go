@@ -0,0 +1,105 @@
+package server
+
+import (
+	"fmt"
+	gohttp "net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/majd/ipatool/v2/pkg/jobs"
+)
+
+// nolint:wrapcheck
+func (s *server) createDownload(req *gohttp.Request) (interface{}, error) {
+	var body DownloadRequest
+
+	err := decodeBody(req, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	if body.AppID == 0 && body.BundleID == "" {
+		return nil, newRequestError(gohttp.StatusBadRequest, ErrorCodeInvalidRequest, "either the app ID or the bundle identifier must be specified")
+	}
+
+	_, err = appstore.ParsePlatform(body.Platform)
+	if err != nil {
+		return nil, newRequestError(gohttp.StatusBadRequest, ErrorCodeInvalidRequest, "%s", err)
+	}
+
+	info, err := s.appStore.AccountInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.jobs.Enqueue(jobs.Request{
+		AppID:             body.AppID,
+		BundleID:          body.BundleID,
+		ExternalVersionID: body.ExternalVersionID,
+		Platform:          body.Platform,
+		Purchase:          body.Purchase,
+		OutputPath:        s.outputPath,
+		Account:           info.Account.Email,
+	})
+}
+
+// nolint:wrapcheck
+func (s *server) listJobs(req *gohttp.Request) (interface{}, error) {
+	result, err := s.jobs.List()
+	if err != nil {
+		return nil, err
+	}
+
+	return jobListResponse{
+		Count: len(result),
+		Jobs:  result,
+	}, nil
+}
+
+// nolint:wrapcheck
+func (s *server) getJob(req *gohttp.Request) (interface{}, error) {
+	return s.jobs.Get(req.PathValue("jobID"))
+}
+
+// nolint:wrapcheck
+func (s *server) cancelJob(req *gohttp.Request) (interface{}, error) {
+	return s.jobs.Cancel(req.PathValue("jobID"))
+}
+
+// nolint:wrapcheck
+func (s *server) retryJob(req *gohttp.Request) (interface{}, error) {
+	return s.jobs.Retry(req.PathValue("jobID"))
+}
+
+// streamPackage writes the app package downloaded by a succeeded job. Range requests are supported
+// so that interrupted transfers can be resumed.
+func (s *server) streamPackage(w gohttp.ResponseWriter, req *gohttp.Request) error {
+	job, err := s.jobs.Get(req.PathValue("jobID"))
+	if err != nil {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+
+	if job.Status != jobs.StatusSucceeded {
+		return newRequestError(gohttp.StatusConflict, ErrorCodeJobNotFinished, "job %s is %s", job.ID, job.Status)
+	}
+
+	file, err := os.Open(job.Output)
+	if err != nil {
+		return fmt.Errorf("failed to open app package: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read app package metadata: %w", err)
+	}
+
+	fileName := filepath.Base(job.Output)
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	gohttp.ServeContent(w, req, fileName, info.ModTime(), file)
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	gohttp "net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/majd/ipatool/v2/pkg/appstore"
+	"github.com/majd/ipatool/v2/pkg/jobs"
+	"github.com/majd/ipatool/v2/pkg/log"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("Server (Jobs)", func() {
+	var (
+		ctrl         *gomock.Controller
+		mockAppStore *appstore.MockAppStore
+		mockQueue    *jobs.MockQueue
+		handler      gohttp.Handler
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockAppStore = appstore.NewMockAppStore(ctrl)
+		mockQueue = jobs.NewMockQueue(ctrl)
+		handler = New(Args{
+			AppStore:   mockAppStore,
+			Jobs:       mockQueue,
+			OutputPath: "/downloads",
+			Logger:     log.NewLogger(log.Args{Writer: io.Discard}),
+			Token:      "token",
+		}).Handler()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	When("a download is requested", func() {
+		BeforeEach(func() {
+			mockAppStore.EXPECT().
+				AccountInfo().
+				Return(appstore.AccountInfoOutput{Account: appstore.Account{Email: "user@example.com"}}, nil)
+
+			mockQueue.EXPECT().
+				Enqueue(jobs.Request{
+					BundleID:   "app.bundle.id",
+					Platform:   "ipad",
+					Purchase:   true,
+					OutputPath: "/downloads",
+					Account:    "user@example.com",
+				}).
+				DoAndReturn(func(request jobs.Request) (jobs.Job, error) {
+					return jobs.Job{ID: "job", Status: jobs.StatusQueued, Request: request}, nil
+				})
+		})
+
+		It("queues a job", func() {
+			res, body := send(handler, gohttp.MethodPost, "/v1/downloads", "token", `{"bundleID":"app.bundle.id","platform":"ipad","purchase":true}`)
+			Expect(res.Code).To(Equal(gohttp.StatusAccepted))
+			Expect(body["id"]).To(Equal("job"))
+			Expect(body["status"]).To(Equal("queued"))
+			Expect(body["request"]).To(HaveKeyWithValue("account", "user@example.com"))
+		})
+	})
+
+	When("no account is logged in", func() {
+		BeforeEach(func() {
+			mockAppStore.EXPECT().
+				AccountInfo().
+				Return(appstore.AccountInfoOutput{}, errors.New("not logged in"))
+		})
+
+		It("does not queue a job", func() {
+			res, _ := send(handler, gohttp.MethodPost, "/v1/downloads", "token", `{"appID":1}`)
+			Expect(res.Code).To(Equal(gohttp.StatusInternalServerError))
+		})
+	})
+
+	When("the job succeeded", func() {
+		var path string
+
+		BeforeEach(func() {
+			path = filepath.Join(GinkgoT().TempDir(), "app.ipa")
+			Expect(os.WriteFile(path, []byte("package"), 0600)).To(Succeed())
+
+			mockQueue.EXPECT().
+				Get("job").
+				Return(jobs.Job{ID: "job", Status: jobs.StatusSucceeded, Output: path, Purchased: true}, nil).
+				AnyTimes()
+		})
+
+		It("returns the job", func() {
+			res, body := send(handler, gohttp.MethodGet, "/v1/jobs/job", "token", "")
+			Expect(res.Code).To(Equal(gohttp.StatusOK))
+			Expect(body["output"]).To(Equal(path))
+			Expect(body["purchased"]).To(BeTrue())
+		})
+
+		It("streams the app package", func() {
+			res, _ := send(handler, gohttp.MethodGet, "/v1/jobs/job/package", "token", "")
+			Expect(res.Code).To(Equal(gohttp.StatusOK))
+			Expect(res.Header().Get("Content-Disposition")).To(Equal(`attachment; filename="app.ipa"`))
+			Expect(res.Body.String()).To(Equal("package"))
+		})
+	})
+
+	When("the job failed", func() {
+		BeforeEach(func() {
+			mockQueue.EXPECT().
+				Get("job").
+				Return(jobs.Job{ID: "job", Status: jobs.StatusFailed, LastErrorCode: "license_required"}, nil)
+		})
+
+		It("does not stream the app package", func() {
+			res, body := send(handler, gohttp.MethodGet, "/v1/jobs/job/package", "token", "")
+			Expect(res.Code).To(Equal(gohttp.StatusConflict))
+			Expect(body["code"]).To(Equal("job_not_finished"))
+		})
+	})
+
+	When("jobs are listed", func() {
+		BeforeEach(func() {
+			mockQueue.EXPECT().
+				List().
+				Return([]jobs.Job{{ID: "first"}, {ID: "second"}}, nil)
+		})
+
+		It("returns the jobs", func() {
+			_, body := send(handler, gohttp.MethodGet, "/v1/jobs", "token", "")
+			Expect(body["count"]).To(BeEquivalentTo(2))
+			Expect(body["jobs"]).To(HaveLen(2))
+		})
+	})
+
+	When("a job is cancelled", func() {
+		BeforeEach(func() {
+			mockQueue.EXPECT().
+				Cancel("job").
+				Return(jobs.Job{ID: "job", Status: jobs.StatusCancelled}, nil)
+		})
+
+		It("returns the job", func() {
+			res, body := send(handler, gohttp.MethodPost, "/v1/jobs/job/cancel", "token", "")
+			Expect(res.Code).To(Equal(gohttp.StatusOK))
+			Expect(body["status"]).To(Equal("cancelled"))
+		})
+	})
+
+	When("a job cannot be retried", func() {
+		BeforeEach(func() {
+			mockQueue.EXPECT().
+				Retry("job").
+				Return(jobs.Job{}, fmt.Errorf("%w: job is queued", jobs.ErrInvalidState))
+		})
+
+		It("returns conflict", func() {
+			res, body := send(handler, gohttp.MethodPost, "/v1/jobs/job/retry", "token", "")
+			Expect(res.Code).To(Equal(gohttp.StatusConflict))
+			Expect(body["code"]).To(Equal("invalid_job_state"))
+		})
+	})
+
+	DescribeTable("rejects invalid download requests",
+		func(body string) {
+			res, decoded := send(handler, gohttp.MethodPost, "/v1/downloads", "token", body)
+			Expect(res.Code).To(Equal(gohttp.StatusBadRequest))
+			Expect(decoded["code"]).To(Equal("invalid_request"))
+		},
+		Entry("missing identifier", `{}`),
+		Entry("invalid platform", `{"appID":1,"platform":"watch"}`),
+		Entry("invalid JSON", `{"appID":`),
+	)
+
+	When("the job does not exist", func() {
+		BeforeEach(func() {
+			mockQueue.EXPECT().
+				Get("unknown").
+				Return(jobs.Job{}, fmt.Errorf("%w: unknown", jobs.ErrJobNotFound))
+		})
+
+		It("returns not found", func() {
+			res, body := send(handler, gohttp.MethodGet, "/v1/jobs/unknown", "token", "")
+			Expect(res.Code).To(Equal(gohttp.StatusNotFound))
+			Expect(body["code"]).To(Equal("not_found"))
+		})
+	})
+})
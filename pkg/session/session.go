@@ -0,0 +1,82 @@
+package session
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	formatVersion = 1
+
+	saltSize = 16
+	keySize  = 32
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var magic = []byte("IPATOOL-SESSION")
+
+var (
+	ErrInvalidArchive       = errors.New("file is not an ipatool session archive")
+	ErrUnsupportedVersion   = errors.New("session archive version is not supported")
+	ErrIntegrityCheckFailed = errors.New("session archive integrity check failed; the passphrase is wrong or the file is corrupted")
+	ErrPassphraseRequired   = errors.New("passphrase is required")
+)
+
+// Bundle holds everything needed to resume an authenticated session on another machine.
+type Bundle struct {
+	Account    []byte    `json:"account"`
+	Cookies    []byte    `json:"cookies,omitempty"`
+	GUID       string    `json:"guid"`
+	ExportedAt time.Time `json:"exportedAt"`
+}
+
+type payload struct {
+	Bundle   Bundle `json:"bundle"`
+	Checksum []byte `json:"checksum"`
+}
+
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcm: %w", err)
+	}
+
+	return aead, nil
+}
+
+func header(salt, nonce []byte) []byte {
+	header := make([]byte, 0, len(magic)+1+len(salt)+len(nonce))
+	header = append(header, magic...)
+	header = append(header, formatVersion)
+	header = append(header, salt...)
+
+	return append(header, nonce...)
+}
+
+func checksum(bundle Bundle) []byte {
+	hash := sha256.New()
+	hash.Write(bundle.Account)
+	hash.Write(bundle.Cookies)
+	hash.Write([]byte(bundle.GUID))
+
+	return hash.Sum(nil)
+}
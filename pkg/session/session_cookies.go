@@ -0,0 +1,47 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// authCookiePrefix identifies the cookies the App Store uses to carry the authentication token.
+const authCookiePrefix = "mz_at"
+
+type cookieEntry struct {
+	Name    string    `json:"Name"`
+	Expires time.Time `json:"Expires"`
+}
+
+// TokenExpired reports whether every App Store authentication cookie in the bundle has expired
+// at the specified time. Bundles without authentication cookies are not considered expired.
+func (b Bundle) TokenExpired(now time.Time) (bool, error) {
+	if len(b.Cookies) == 0 {
+		return false, nil
+	}
+
+	var entries []cookieEntry
+
+	err := json.Unmarshal(b.Cookies, &entries)
+	if err != nil {
+		return false, fmt.Errorf("failed to unmarshal cookies: %w", err)
+	}
+
+	found := false
+
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name, authCookiePrefix) {
+			continue
+		}
+
+		found = true
+
+		if entry.Expires.IsZero() || entry.Expires.After(now) {
+			return false, nil
+		}
+	}
+
+	return found, nil
+}
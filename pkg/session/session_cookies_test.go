@@ -0,0 +1,57 @@
+package session
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session (TokenExpired)", func() {
+	var now = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	When("bundle has no cookies", func() {
+		It("returns false", func() {
+			expired, err := Bundle{}.TokenExpired(now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(expired).To(BeFalse())
+		})
+	})
+
+	When("cookies are malformed", func() {
+		It("returns error", func() {
+			_, err := Bundle{Cookies: []byte("{")}.TokenExpired(now)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("there are no authentication cookies", func() {
+		It("returns false", func() {
+			expired, err := Bundle{
+				Cookies: []byte(`[{"Name":"itspod","Expires":"2020-01-01T00:00:00Z"}]`),
+			}.TokenExpired(now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(expired).To(BeFalse())
+		})
+	})
+
+	When("all authentication cookies expired", func() {
+		It("returns true", func() {
+			expired, err := Bundle{
+				Cookies: []byte(`[{"Name":"mz_at0-1","Expires":"2020-01-01T00:00:00Z"},{"Name":"mz_at_ssl-1","Expires":"2021-01-01T00:00:00Z"}]`),
+			}.TokenExpired(now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(expired).To(BeTrue())
+		})
+	})
+
+	When("an authentication cookie is still valid", func() {
+		It("returns false", func() {
+			expired, err := Bundle{
+				Cookies: []byte(`[{"Name":"mz_at0-1","Expires":"2020-01-01T00:00:00Z"},{"Name":"mz_at_ssl-1","Expires":"2025-01-01T00:00:00Z"}]`),
+			}.TokenExpired(now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(expired).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,56 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Open decrypts an archive produced by Seal and verifies its integrity.
+func Open(data []byte, passphrase string) (Bundle, error) {
+	if passphrase == "" {
+		return Bundle{}, ErrPassphraseRequired
+	}
+
+	if !bytes.HasPrefix(data, magic) || len(data) < len(magic)+1+saltSize {
+		return Bundle{}, ErrInvalidArchive
+	}
+
+	if data[len(magic)] != formatVersion {
+		return Bundle{}, fmt.Errorf("%w: %d", ErrUnsupportedVersion, data[len(magic)])
+	}
+
+	offset := len(magic) + 1
+	salt := data[offset : offset+saltSize]
+	offset += saltSize
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	if len(data) < offset+aead.NonceSize()+aead.Overhead() {
+		return Bundle{}, ErrInvalidArchive
+	}
+
+	nonce := data[offset : offset+aead.NonceSize()]
+	offset += aead.NonceSize()
+
+	plaintext, err := aead.Open(nil, nonce, data[offset:], data[:offset])
+	if err != nil {
+		return Bundle{}, ErrIntegrityCheckFailed
+	}
+
+	var decoded payload
+
+	err = json.Unmarshal(plaintext, &decoded)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("failed to unmarshal bundle: %w", err)
+	}
+
+	if !bytes.Equal(decoded.Checksum, checksum(decoded.Bundle)) {
+		return Bundle{}, ErrIntegrityCheckFailed
+	}
+
+	return decoded.Bundle, nil
+}
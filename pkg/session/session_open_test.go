@@ -0,0 +1,82 @@
+package session
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session (Open)", func() {
+	var (
+		testBundle = Bundle{
+			Account:    []byte(`{"email":"test@example.com"}`),
+			Cookies:    []byte(`[]`),
+			GUID:       "0011223344AA",
+			ExportedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+		archive []byte
+	)
+
+	BeforeEach(func() {
+		var err error
+		archive, err = Seal(testBundle, "secret")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	When("passphrase is correct", func() {
+		It("returns bundle", func() {
+			bundle, err := Open(archive, "secret")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bundle).To(Equal(testBundle))
+		})
+	})
+
+	When("passphrase is empty", func() {
+		It("returns error", func() {
+			_, err := Open(archive, "")
+			Expect(err).To(MatchError(ErrPassphraseRequired))
+		})
+	})
+
+	When("passphrase is wrong", func() {
+		It("returns error", func() {
+			_, err := Open(archive, "wrong")
+			Expect(err).To(MatchError(ErrIntegrityCheckFailed))
+		})
+	})
+
+	When("archive is tampered with", func() {
+		It("returns error", func() {
+			archive[len(archive)-1] ^= 0xFF
+
+			_, err := Open(archive, "secret")
+			Expect(err).To(MatchError(ErrIntegrityCheckFailed))
+		})
+	})
+
+	When("header is tampered with", func() {
+		It("returns error", func() {
+			archive[len(magic)+1] ^= 0xFF
+
+			_, err := Open(archive, "secret")
+			Expect(err).To(MatchError(ErrIntegrityCheckFailed))
+		})
+	})
+
+	When("file is not an archive", func() {
+		It("returns error", func() {
+			_, err := Open([]byte("not an archive"), "secret")
+			Expect(err).To(MatchError(ErrInvalidArchive))
+		})
+	})
+
+	When("archive version is not supported", func() {
+		It("returns error", func() {
+			archive[len(magic)] = formatVersion + 1
+
+			_, err := Open(archive, "secret")
+			Expect(err).To(MatchError(ErrUnsupportedVersion))
+		})
+	})
+})
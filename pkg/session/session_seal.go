@@ -0,0 +1,47 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+)
+
+// Seal serializes the bundle and encrypts it with a key derived from the passphrase using scrypt.
+// The archive layout is: magic | version | salt | nonce | AES-GCM ciphertext. The header is
+// authenticated as additional data so that tampering with any part of the file is detected.
+func Seal(bundle Bundle, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, ErrPassphraseRequired
+	}
+
+	salt := make([]byte, saltSize)
+
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	plaintext, err := json.Marshal(payload{
+		Bundle:   bundle,
+		Checksum: checksum(bundle),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bundle: %w", err)
+	}
+
+	header := header(salt, nonce)
+
+	return aead.Seal(header, nonce, plaintext, header), nil
+}
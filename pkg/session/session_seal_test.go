@@ -0,0 +1,44 @@
+package session
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session (Seal)", func() {
+	var testBundle = Bundle{
+		Account:    []byte(`{"email":"test@example.com"}`),
+		Cookies:    []byte(`[]`),
+		GUID:       "0011223344AA",
+		ExportedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	When("passphrase is empty", func() {
+		It("returns error", func() {
+			_, err := Seal(testBundle, "")
+			Expect(err).To(MatchError(ErrPassphraseRequired))
+		})
+	})
+
+	When("passphrase is set", func() {
+		It("returns encrypted archive", func() {
+			data, err := Seal(testBundle, "secret")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(HavePrefix(string(magic)))
+			Expect(bytes.Contains(data, testBundle.Account)).To(BeFalse())
+		})
+
+		It("uses a unique salt and nonce for every archive", func() {
+			first, err := Seal(testBundle, "secret")
+			Expect(err).ToNot(HaveOccurred())
+
+			second, err := Seal(testBundle, "secret")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(first).ToNot(Equal(second))
+		})
+	})
+})
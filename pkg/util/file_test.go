@@ -0,0 +1,56 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteFileAtomic", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	It("replaces the file", func() {
+		path := filepath.Join(dir, "file.json")
+
+		err := os.WriteFile(path, []byte("old"), 0600)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = WriteFileAtomic(path, []byte("new"), 0600)
+		Expect(err).ToNot(HaveOccurred())
+
+		data, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("new"))
+
+		entries, err := os.ReadDir(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("sets the file mode", func() {
+		if runtime.GOOS == "windows" {
+			Skip("file modes are not supported on windows")
+		}
+
+		path := filepath.Join(dir, "file.json")
+
+		err := WriteFileAtomic(path, []byte("data"), 0644)
+		Expect(err).ToNot(HaveOccurred())
+
+		info, err := os.Stat(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0644)))
+	})
+
+	It("returns error when the directory does not exist", func() {
+		err := WriteFileAtomic(filepath.Join(dir, "missing", "file.json"), []byte("data"), 0600)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,7 @@
+// Package filelock provides exclusive locks on files shared between processes.
+package filelock
+
+import "errors"
+
+// ErrLocked is returned when the file is locked by another process and the caller does not wait.
+var ErrLocked = errors.New("file is locked by another process")
@@ -0,0 +1,57 @@
+package filelock
+
+import (
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestFileLock(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "File Lock Suite")
+}
+
+var _ = Describe("File Lock", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "file.lock")
+	})
+
+	When("the file is not locked", func() {
+		It("locks the file", func() {
+			file, err := Lock(path, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(file.Name()).To(Equal(path))
+
+			Unlock(file)
+		})
+	})
+
+	When("the file is locked", func() {
+		BeforeEach(func() {
+			file, err := Lock(path, false)
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(Unlock, file)
+		})
+
+		It("returns error instead of waiting", func() {
+			_, err := Lock(path, false)
+			Expect(err).To(MatchError(ErrLocked))
+		})
+	})
+
+	When("the lock is released", func() {
+		It("locks the file again", func() {
+			file, err := Lock(path, true)
+			Expect(err).ToNot(HaveOccurred())
+			Unlock(file)
+
+			file, err = Lock(path, false)
+			Expect(err).ToNot(HaveOccurred())
+			Unlock(file)
+		})
+	})
+})
@@ -0,0 +1,43 @@
+//go:build !windows
+
+package filelock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Lock opens the file, creating it if needed, and locks it exclusively. When wait is false, ErrLocked
+// is returned instead of waiting for another process to release the lock.
+func Lock(path string, wait bool) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	how := syscall.LOCK_EX
+	if !wait {
+		how |= syscall.LOCK_NB
+	}
+
+	err = syscall.Flock(int(file.Fd()), how)
+	if err != nil {
+		_ = file.Close()
+
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, ErrLocked
+		}
+
+		return nil, fmt.Errorf("failed to lock file: %w", err)
+	}
+
+	return file, nil
+}
+
+// Unlock releases the lock and closes the file.
+func Unlock(file *os.File) {
+	_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+	_ = file.Close()
+}
@@ -0,0 +1,44 @@
+//go:build windows
+
+package filelock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// Lock opens the file, creating it if needed, and locks it exclusively. When wait is false, ErrLocked
+// is returned instead of waiting for another process to release the lock.
+func Lock(path string, wait bool) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	flags := uint32(windows.LOCKFILE_EXCLUSIVE_LOCK)
+	if !wait {
+		flags |= windows.LOCKFILE_FAIL_IMMEDIATELY
+	}
+
+	err = windows.LockFileEx(windows.Handle(file.Fd()), flags, 0, 1, 0, &windows.Overlapped{})
+	if err != nil {
+		_ = file.Close()
+
+		if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+			return nil, ErrLocked
+		}
+
+		return nil, fmt.Errorf("failed to lock file: %w", err)
+	}
+
+	return file, nil
+}
+
+// Unlock releases the lock and closes the file.
+func Unlock(file *os.File) {
+	_ = windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, &windows.Overlapped{})
+	_ = file.Close()
+}
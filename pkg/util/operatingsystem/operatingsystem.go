@@ -14,6 +14,9 @@ type OperatingSystem interface {
 	IsNotExist(err error) bool
 	MkdirAll(path string, perm os.FileMode) error
 	Rename(oldPath, newPath string) error
+	// FreeSpace returns the number of bytes available to the process on the file system of the path. It
+	// returns errors.ErrUnsupported on platforms where the free space cannot be determined.
+	FreeSpace(path string) (uint64, error)
 }
 
 type operatingSystem struct{}
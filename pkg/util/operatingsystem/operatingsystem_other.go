@@ -0,0 +1,9 @@
+//go:build !darwin && !freebsd && !linux && !windows
+
+package operatingsystem
+
+import "errors"
+
+func (operatingSystem) FreeSpace(string) (uint64, error) {
+	return 0, errors.ErrUnsupported
+}
@@ -0,0 +1,20 @@
+//go:build darwin || freebsd || linux
+
+package operatingsystem
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+func (operatingSystem) FreeSpace(path string) (uint64, error) {
+	var stat unix.Statfs_t
+
+	err := unix.Statfs(path, &stat)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file system statistics: %w", err)
+	}
+
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil // nolint:unconvert
+}
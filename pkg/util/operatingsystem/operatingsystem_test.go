@@ -106,4 +106,17 @@ var _ = Describe("OperatingSystem", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
+
+	When("reading the free space", func() {
+		It("returns the available bytes", func() {
+			res, err := sut.FreeSpace(os.TempDir())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(BeNumerically(">", 0))
+		})
+
+		It("returns error when the path does not exist", func() {
+			_, err := sut.FreeSpace(path.Join(os.TempDir(), "ipatool-missing", "dir"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
@@ -0,0 +1,25 @@
+//go:build windows
+
+package operatingsystem
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+func (operatingSystem) FreeSpace(path string) (uint64, error) {
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, fmt.Errorf("invalid path: %w", err)
+	}
+
+	var available uint64
+
+	err = windows.GetDiskFreeSpaceEx(name, &available, nil, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read disk free space: %w", err)
+	}
+
+	return available, nil
+}
@@ -0,0 +1,31 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseRate parses a number of bytes per second with an optional binary unit, e.g. "512K", "20M" or "1.5G".
+func ParseRate(value string) (int64, error) {
+	units := map[string]float64{
+		"k": 1 << 10,
+		"m": 1 << 20,
+		"g": 1 << 30,
+	}
+
+	number, unit := value, 1.0
+
+	if len(value) > 0 {
+		if multiplier, ok := units[strings.ToLower(value[len(value)-1:])]; ok {
+			number, unit = value[:len(value)-1], multiplier
+		}
+	}
+
+	rate, err := strconv.ParseFloat(number, 64)
+	if err != nil || rate <= 0 {
+		return 0, fmt.Errorf("invalid rate %q; rates are a number of bytes per second, e.g. 512K or 20M", value)
+	}
+
+	return int64(rate * unit), nil
+}
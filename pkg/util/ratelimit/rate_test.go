@@ -0,0 +1,33 @@
+package ratelimit
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rate", func() {
+	DescribeTable("parses rates",
+		func(value string, expected int64) {
+			rate, err := ParseRate(value)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rate).To(Equal(expected))
+		},
+		Entry("bytes", "1000", int64(1000)),
+		Entry("kibibytes", "512K", int64(512<<10)),
+		Entry("mebibytes", "20M", int64(20<<20)),
+		Entry("lowercase unit", "20m", int64(20<<20)),
+		Entry("fraction", "1.5G", int64(3<<29)),
+	)
+
+	DescribeTable("rejects invalid rates",
+		func(value string) {
+			_, err := ParseRate(value)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("empty", ""),
+		Entry("unit only", "M"),
+		Entry("unknown unit", "20T"),
+		Entry("zero", "0"),
+		Entry("negative", "-1M"),
+	)
+})
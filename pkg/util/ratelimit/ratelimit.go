@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// Limiter limits the rate of the transfers sharing it with a token bucket, and pauses them during the
+// windows of its schedule.
+//
+//go:generate go run go.uber.org/mock/mockgen -source=ratelimit.go -destination=ratelimit_mock.go -package ratelimit
+type Limiter interface {
+	// WaitN blocks until n bytes may be transferred. WaitN(ctx, 0) only waits for the end of a pause.
+	WaitN(ctx context.Context, n int) error
+	// Reader returns a reader whose reads are limited by the limiter.
+	Reader(ctx context.Context, reader io.Reader) io.Reader
+}
+
+type Args struct {
+	// Rate is the number of bytes transferred per second. Zero disables the limit.
+	Rate int64
+	// Schedule pauses the transfers during its windows.
+	Schedule Schedule
+}
+
+type limiter struct {
+	rate     int64
+	schedule Schedule
+	now      func() time.Time
+	sleep    func(ctx context.Context, duration time.Duration) error
+
+	mutex  sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func New(args Args) Limiter {
+	return &limiter{
+		rate:     args.Rate,
+		schedule: args.Schedule,
+		now:      time.Now,
+		sleep:    sleep,
+	}
+}
+
+func (l *limiter) WaitN(ctx context.Context, n int) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	pause := l.schedule.Remaining(l.now())
+	if pause > 0 {
+		err := l.sleep(ctx, pause)
+		if err != nil {
+			return err
+		}
+	}
+
+	if l.rate <= 0 || n <= 0 {
+		return nil
+	}
+
+	l.mutex.Lock()
+
+	now := l.now()
+	burst := float64(l.burst())
+
+	if l.last.IsZero() {
+		l.tokens = burst
+	} else {
+		l.tokens = min(burst, l.tokens+now.Sub(l.last).Seconds()*float64(l.rate))
+	}
+
+	// The bucket may go into debt, which is paid off by the waits of the following transfers.
+	l.last = now
+	l.tokens -= float64(n)
+	wait := time.Duration(-l.tokens / float64(l.rate) * float64(time.Second))
+
+	l.mutex.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	return l.sleep(ctx, wait)
+}
+
+func (l *limiter) Reader(ctx context.Context, reader io.Reader) io.Reader {
+	return &limitedReader{
+		ctx:     ctx,
+		reader:  reader,
+		limiter: l,
+	}
+}
+
+// burst returns the number of bytes which may be transferred at once.
+func (l *limiter) burst() int64 {
+	return max(l.rate, 1)
+}
+
+type limitedReader struct {
+	ctx     context.Context
+	reader  io.Reader
+	limiter *limiter
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.limiter.rate > 0 && int64(len(p)) > r.limiter.burst() {
+		p = p[:r.limiter.burst()]
+	}
+
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		waitErr := r.limiter.WaitN(r.ctx, n)
+		if waitErr != nil {
+			return n, waitErr
+		}
+	}
+
+	return n, err // nolint:wrapcheck
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err() // nolint:wrapcheck
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRateLimit(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Rate Limit Suite")
+}
+
+var _ = Describe("Limiter", func() {
+	var (
+		sut    *limiter
+		now    time.Time
+		sleeps []time.Duration
+	)
+
+	newLimiter := func(args Args) *limiter {
+		l := New(args).(*limiter)
+		l.now = func() time.Time { return now }
+		l.sleep = func(ctx context.Context, duration time.Duration) error {
+			sleeps = append(sleeps, duration)
+			now = now.Add(duration)
+
+			return ctx.Err()
+		}
+
+		return l
+	}
+
+	BeforeEach(func() {
+		now = time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local)
+		sleeps = nil
+	})
+
+	When("the rate is not limited", func() {
+		BeforeEach(func() {
+			sut = newLimiter(Args{})
+		})
+
+		It("does not wait", func() {
+			Expect(sut.WaitN(context.Background(), 1<<30)).To(Succeed())
+			Expect(sleeps).To(BeEmpty())
+		})
+	})
+
+	When("the rate is limited", func() {
+		BeforeEach(func() {
+			sut = newLimiter(Args{Rate: 100})
+		})
+
+		It("allows a burst of one second", func() {
+			Expect(sut.WaitN(context.Background(), 100)).To(Succeed())
+			Expect(sleeps).To(BeEmpty())
+		})
+
+		It("waits until the tokens are refilled", func() {
+			Expect(sut.WaitN(context.Background(), 100)).To(Succeed())
+			Expect(sut.WaitN(context.Background(), 50)).To(Succeed())
+			Expect(sleeps).To(Equal([]time.Duration{500 * time.Millisecond}))
+		})
+
+		It("shares the rate between readers", func() {
+			first := sut.Reader(context.Background(), strings.NewReader(strings.Repeat("a", 150)))
+			second := sut.Reader(context.Background(), strings.NewReader(strings.Repeat("b", 150)))
+
+			_, err := io.ReadAll(first)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = io.ReadAll(second)
+			Expect(err).ToNot(HaveOccurred())
+
+			var total time.Duration
+			for _, duration := range sleeps {
+				total += duration
+			}
+
+			Expect(total).To(Equal(2 * time.Second))
+		})
+
+		It("returns error when the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			Expect(sut.WaitN(ctx, 100)).To(Succeed())
+			Expect(sut.WaitN(ctx, 100)).To(MatchError(context.Canceled))
+		})
+	})
+
+	When("transfers are paused", func() {
+		BeforeEach(func() {
+			sut = newLimiter(Args{Schedule: Schedule{{Start: 11 * time.Hour, End: 13 * time.Hour}}})
+		})
+
+		It("waits until the end of the window", func() {
+			Expect(sut.WaitN(context.Background(), 0)).To(Succeed())
+			Expect(sleeps).To(Equal([]time.Duration{time.Hour}))
+		})
+	})
+})
@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a time of day range, e.g. from 09:00 to 18:00. Windows ending before they start span midnight.
+type Window struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Schedule is a list of time of day windows, in local time.
+type Schedule []Window
+
+// ParseSchedule parses a comma-separated list of windows, e.g. "09:00-12:00,13:00-18:00" or "22:00-06:00".
+func ParseSchedule(value string) (Schedule, error) {
+	var schedule Schedule
+
+	for _, text := range strings.Split(value, ",") {
+		start, end, ok := strings.Cut(strings.TrimSpace(text), "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid window %q; windows must be in the form HH:MM-HH:MM", text)
+		}
+
+		startTime, startErr := time.Parse("15:04", start)
+		endTime, endErr := time.Parse("15:04", end)
+
+		if startErr != nil || endErr != nil {
+			return nil, fmt.Errorf("invalid window %q; windows must be in the form HH:MM-HH:MM", text)
+		}
+
+		window := Window{
+			Start: time.Duration(startTime.Hour())*time.Hour + time.Duration(startTime.Minute())*time.Minute,
+			End:   time.Duration(endTime.Hour())*time.Hour + time.Duration(endTime.Minute())*time.Minute,
+		}
+
+		if window.Start == window.End {
+			return nil, fmt.Errorf("invalid window %q; windows must not be empty", text)
+		}
+
+		schedule = append(schedule, window)
+	}
+
+	return schedule, nil
+}
+
+// Remaining returns the time until the end of the windows containing the specified time, or zero when
+// it is outside of every window.
+func (s Schedule) Remaining(now time.Time) time.Duration {
+	var remaining time.Duration
+
+	// Adjacent or overlapping windows are followed until the end of the last one.
+	for remaining < 24*time.Hour {
+		r := s.remainingAt(now.Add(remaining))
+		if r == 0 {
+			return remaining
+		}
+
+		remaining += r
+	}
+
+	return remaining
+}
+
+func (s Schedule) remainingAt(now time.Time) time.Duration {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	offset := now.Sub(midnight)
+
+	for _, window := range s {
+		switch {
+		case window.Start < window.End && offset >= window.Start && offset < window.End:
+			return window.End - offset
+		case window.Start > window.End && offset >= window.Start:
+			return 24*time.Hour - offset + window.End
+		case window.Start > window.End && offset < window.End:
+			return window.End - offset
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schedule", func() {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 5, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	It("parses windows", func() {
+		schedule, err := ParseSchedule("09:00-12:30, 22:00-06:00")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(schedule).To(Equal(Schedule{
+			{Start: 9 * time.Hour, End: 12*time.Hour + 30*time.Minute},
+			{Start: 22 * time.Hour, End: 6 * time.Hour},
+		}))
+	})
+
+	DescribeTable("rejects invalid windows",
+		func(value string) {
+			_, err := ParseSchedule(value)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("missing end", "09:00"),
+		Entry("invalid time", "09:00-25:00"),
+		Entry("empty window", "09:00-09:00"),
+	)
+
+	DescribeTable("returns the remaining pause",
+		func(schedule Schedule, now time.Time, expected time.Duration) {
+			Expect(schedule.Remaining(now)).To(Equal(expected))
+		},
+		Entry("outside of the windows", Schedule{{Start: 9 * time.Hour, End: 18 * time.Hour}}, at(8, 0), time.Duration(0)),
+		Entry("at the end of a window", Schedule{{Start: 9 * time.Hour, End: 18 * time.Hour}}, at(18, 0), time.Duration(0)),
+		Entry("within a window", Schedule{{Start: 9 * time.Hour, End: 18 * time.Hour}}, at(17, 30), 30*time.Minute),
+		Entry("before midnight", Schedule{{Start: 22 * time.Hour, End: 6 * time.Hour}}, at(23, 0), 7*time.Hour),
+		Entry("after midnight", Schedule{{Start: 22 * time.Hour, End: 6 * time.Hour}}, at(5, 0), time.Hour),
+		Entry("adjacent windows", Schedule{{Start: 9 * time.Hour, End: 12 * time.Hour}, {Start: 12 * time.Hour, End: 13 * time.Hour}}, at(11, 0), 2*time.Hour),
+		Entry("whole day", Schedule{{Start: 0, End: 12 * time.Hour}, {Start: 12 * time.Hour, End: 0}}, at(11, 0), 25*time.Hour),
+	)
+})